@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+// agentRefHelp documents the agent references accepted in place of an ID
+const agentRefHelp = `Agents can be referenced by their full ID, a unique prefix of it (with or
+without "bc_"), their name, @last (the most recently created agent) or
+@last-running (the most recently created running agent).`
+
+// resolveAgentID resolves an agent reference given on the command line to
+// an agent ID, exiting if it is ambiguous or cannot be looked up
+func resolveAgentID(ctx context.Context, apiClient *client.Client, ref string) string {
+	agentID, err := apiClient.ResolveAgentRef(ctx, ref)
+	if err != nil {
+		exitWithError("Error resolving agent", err)
+	}
+	return agentID
+}
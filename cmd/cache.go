@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command group
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clear the local agent cache",
+	Long: `Agents and conversations are saved in a local cache as they are fetched,
+one cache per profile, under $XDG_CACHE_HOME/cursor-cli (~/.cache/cursor-cli
+by default). Pass --offline to any command to read from the cache instead
+of the API, e.g. without network access; output then notes how old the
+data is.`,
+}
+
+// cacheInfoCmd represents the cache info command
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show what the local cache holds for the active profile",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := openCache()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		info, err := store.Info()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(info, cacheInfoTable(info))
+			return
+		}
+
+		out := textOutput()
+		fmt.Fprintf(out, decorated("📁 Directory:     %s\n"), info.Dir)
+		fmt.Fprintf(out, decorated("👤 Profile:       %s\n"), config.ActiveProfile())
+		fmt.Fprintf(out, decorated("🤖 Agents:        %d\n"), info.Entries["agents"])
+		fmt.Fprintf(out, decorated("💬 Conversations: %d\n"), info.Entries["conversations"])
+		fmt.Fprintf(out, decorated("💾 Size:          %.1f KiB\n"), float64(info.Size)/1024)
+		if !info.Newest.IsZero() {
+			fmt.Fprintf(out, decorated("🕐 Last fetched:  %s (%s)\n"), info.Newest.Local().Format("2006-01-02 15:04"), cacheAge(info.Newest))
+			fmt.Fprintf(out, decorated("🕰️  Oldest entry:  %s (%s)\n"), info.Oldest.Local().Format("2006-01-02 15:04"), cacheAge(info.Oldest))
+		}
+	},
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the cached agents and conversations",
+	Long: `Remove the cached agents and conversations of the active profile, or of
+every profile with --all. The cache is filled again as agents are fetched.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+
+		var store *cache.Store
+		if all {
+			dir, err := cache.DefaultDir()
+			if err != nil {
+				printError("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			store = cache.Open(dir)
+		} else {
+			var err error
+			if store, err = openCache(); err != nil {
+				printError("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := store.Clear(); err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if all {
+			fmt.Printf(decorated("✅ Cleared the cache of every profile (%s)\n"), store.Dir())
+		} else {
+			fmt.Printf(decorated("✅ Cleared the cache of profile %q (%s)\n"), config.ActiveProfile(), store.Dir())
+		}
+	},
+}
+
+// openCache returns the cache of the active profile
+func openCache() (*cache.Store, error) {
+	// The profile names a directory, possibly one cache clear removes
+	profile := config.ActiveProfile()
+	if err := config.ValidateProfileName(profile); err != nil {
+		return nil, err
+	}
+
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return cache.Open(filepath.Join(dir, profile)), nil
+}
+
+// printCacheNotice notes when the data shown was read from the local
+// cache, and how old it is. The notice goes to stderr unless text is shown
+// on a terminal, so it never ends up in piped output.
+func printCacheNotice(apiClient *client.Client) {
+	cachedAt := apiClient.CachedAt()
+	if cachedAt.IsZero() {
+		return
+	}
+
+	notice := fmt.Sprintf("Offline: showing cached data fetched %s (%s)", cacheAge(cachedAt), cachedAt.Local().Format("2006-01-02 15:04"))
+	if outputOpts.IsText() && output.IsTerminal(os.Stdout) {
+		fmt.Printf("\n📴 %s\n", notice)
+	} else {
+		fmt.Fprintln(os.Stderr, notice)
+	}
+}
+
+// cacheAge describes how long ago t was, e.g. "5m ago"
+func cacheAge(t time.Time) string {
+	age := time.Since(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	// Add flags
+	cacheClearCmd.Flags().Bool("all", false, "Clear the cache of every profile")
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the API requests made while completing, so a
+// slow network doesn't hang the shell
+const completionTimeout = 5 * time.Second
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for your shell. Besides commands and flags,
+it completes agent IDs (with their name and status), --output formats and
+profile names. Agents are looked up with your API key and cached for a
+short while.
+
+Bash (requires the bash-completion package):
+  source <(cursor-cli completion bash)
+  # or, to load it in every session:
+  cursor-cli completion bash > /etc/bash_completion.d/cursor-cli
+
+Zsh:
+  cursor-cli completion zsh > "${fpath[1]}/_cursor-cli"
+  # completion must be enabled with 'autoload -U compinit; compinit'
+
+Fish:
+  cursor-cli completion fish > ~/.config/fish/completions/cursor-cli.fish
+
+PowerShell:
+  cursor-cli completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		if err != nil {
+			printError("❌ Error generating completion script: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// completeAgentRefs returns a ValidArgsFunction completing agent
+// references for the first n arguments, or every argument if n is
+// negative. Agents already on the command line are not offered again.
+func completeAgentRefs(n int) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if n >= 0 && len(args) >= n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		given := make(map[string]bool, len(args))
+		for _, arg := range args {
+			given[arg] = true
+		}
+
+		completions := []string{
+			client.RefLast + "\tMost recently created agent",
+			client.RefLastRunning + "\tMost recently created running agent",
+		}
+		for _, agent := range completionAgents(cmd.Context()) {
+			if given[agent.ID] {
+				continue
+			}
+			description := agent.Status
+			if agent.Name != "" {
+				description = agent.Name + " (" + agent.Status + ")"
+			}
+			completions = append(completions, agent.ID+"\t"+description)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionAgents returns the agents of the active profile, from the
+// client's cached agent list if it is fresh and from the API otherwise.
+// Errors are ignored: completion then just offers no agents.
+func completionAgents(ctx context.Context) []client.Agent {
+	// The completed command line may select another config file
+	if err := config.Load(cfgFile); err != nil {
+		return nil
+	}
+
+	// A passphrase prompt would hang the shell waiting for the completion, so
+	// offer no agents when the key can't be read without one
+	config.Interactive = false
+	apiKey, err := config.GetAPIKey()
+	if err != nil {
+		return nil
+	}
+	apiClient, err := client.New(apiKey, clientOptions()...)
+	if err != nil {
+		return nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	agents, err := apiClient.KnownAgents(ctx)
+	if err != nil {
+		return nil
+	}
+	return agents
+}
+
+// completeOutputFormats completes the --output flag
+func completeOutputFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	descriptions := map[output.Format]string{
+		output.FormatText:     "Human-readable text",
+		output.FormatJSON:     "JSON",
+		output.FormatYAML:     "YAML",
+		output.FormatNDJSON:   "One JSON object per line",
+		output.FormatCSV:      "Comma-separated values",
+		output.FormatTemplate: "Go template, e.g. template={{.ID}}",
+	}
+
+	template := string(output.FormatTemplate) + "="
+	if strings.HasPrefix(toComplete, template) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, format := range []output.Format{output.FormatText, output.FormatJSON, output.FormatYAML, output.FormatNDJSON, output.FormatCSV} {
+		completions = append(completions, string(format)+"\t"+descriptions[format])
+	}
+	completions = append(completions, template+"\t"+descriptions[output.FormatTemplate])
+
+	// Leave the cursor after "template=" so the template can be typed
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if toComplete != "" && strings.HasPrefix(template, toComplete) {
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+	return completions, directive
+}
+
+// completeProfiles completes profile names from the config file
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if err := config.Load(cfgFile); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.Profiles(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	// Replace cobra's default completion command with the one above
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command group
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage cursor-cli configuration and profiles",
+	Long: `Manage cursor-cli configuration and profiles.
+
+Settings are read and written with get, set and unset, which validate
+values against the known keys. Keys that belong to a profile (api_key,
+base_url, repository, ...) apply to the active profile.
+
+Profiles let you keep several accounts side by side, each with its own API
+key, base URL, default repository and launch defaults. Select a profile for
+a single command with --profile or CURSOR_PROFILE, or make it the default
+with 'cursor-cli config use-profile'.`,
+}
+
+// useProfileCmd represents the config use-profile command
+var useProfileCmd = &cobra.Command{
+	Use:   "use-profile <name>",
+	Short: "Set the profile used by default",
+	Long: `Make the given profile the one used when --profile and CURSOR_PROFILE
+are not set.
+
+Create a new profile by running init with it selected:
+  cursor-cli --profile work init
+
+Example:
+  cursor-cli config use-profile work`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfiles,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.UseProfile(args[0]); err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf(decorated("✅ Now using profile %q\n"), args[0])
+	},
+}
+
+// profilesCmd represents the config profiles command
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List configured profiles",
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles := config.Profiles()
+		if len(profiles) == 0 {
+			fmt.Println(decorated("📭 No profiles configured. Run 'cursor-cli init' to create one."))
+			return
+		}
+
+		active := config.ActiveProfile()
+		for _, profile := range profiles {
+			marker := " "
+			if profile == active {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, profile)
+		}
+	},
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Long: `Print the effective value of a setting, taking flags and environment
+variables into account.
+
+Example:
+  cursor-cli config get timeout`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, ok, err := config.Get(args[0])
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s is not set\n", args[0])
+			os.Exit(1)
+		}
+
+		fmt.Println(value)
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the config file",
+	Long: `Change a setting in the config file. The value is checked against the
+type of the key, e.g. durations like 30s or 2m for timeout.
+
+Examples:
+  cursor-cli config set timeout 1m
+  cursor-cli config set output json
+  cursor-cli --profile work config set base_url https://api.example.com/v0`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.Set(args[0], args[1]); err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf(decorated("✅ Set %s\n"), strings.ToLower(args[0]))
+	},
+}
+
+// configUnsetCmd represents the config unset command
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting from the config file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.Unset(args[0]); err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf(decorated("✅ Unset %s\n"), strings.ToLower(args[0]))
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the settings in the config file",
+	Long: `List the known settings in the config file for the active profile.
+Secrets such as the API key are redacted. Pass --keys to list every key
+cursor-cli understands instead.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if keys, _ := cmd.Flags().GetBool("keys"); keys {
+			for _, setting := range config.Settings {
+				scope := ""
+				if setting.Profile {
+					scope = " (profile)"
+				}
+				fmt.Printf("%-22s %-9s %s%s\n", setting.Key, setting.Type, setting.Description, scope)
+			}
+			return
+		}
+
+		entries, err := config.List()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(entries, configTable(entries))
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Println(decorated("📭 No settings configured. Change one with 'cursor-cli config set <key> <value>'."))
+			return
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s = %s\n", entry.Key, entry.Value)
+		}
+	},
+}
+
+// configPathCmd represents the config path command
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.Path()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(path)
+	},
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in your editor",
+	Long: `Open the config file in $VISUAL or $EDITOR, creating it if needed. The
+file is checked against the known keys once the editor exits.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.EnsureFile()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		editor := strings.Fields(editorCommand())
+		editCmd := exec.Command(editor[0], append(editor[1:], path)...)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		if err := editCmd.Run(); err != nil {
+			printError("❌ Error running editor: %v\n", err)
+			os.Exit(1)
+		}
+
+		if errs := config.ValidateFile(); len(errs) > 0 {
+			printError("⚠️  %s has invalid settings:\n", path)
+			for _, err := range errs {
+				printError("   - %v\n", err)
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+// editorCommand returns the user's preferred editor
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(useProfileCmd)
+	configCmd.AddCommand(profilesCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configEditCmd)
+
+	// Add flags
+	configListCmd.Flags().Bool("keys", false, "List all known keys and their types")
+}
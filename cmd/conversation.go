@@ -3,9 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
-	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/export"
 	"github.com/spf13/cobra"
 )
 
@@ -16,45 +17,141 @@ var conversationCmd = &cobra.Command{
 	Long: `Retrieve the conversation history of a background agent.
 	
 This command shows all messages in the agent's conversation, including
-user messages and agent responses.`,
-	Args: cobra.ExactArgs(1),
+user messages and agent responses.
+
+With --export, the conversation is written as a document with the agent's
+metadata (name, repository, ref, branch, pull request, summary) instead:
+  md    Markdown, with message text and code blocks kept as is
+  html  a self-contained, styled HTML page
+  json  the agent and its conversation
+  txt   plain text
+The document goes to stdout, or to the file given with -o, whose extension
+selects the format when --export is left out.
+
+` + agentRefHelp + `
+
+Examples:
+  cursor-cli conversation @last
+  cursor-cli conversation bc_abc123 --export md -o transcript.md
+  cursor-cli conversation bc_abc123 -o transcript.html`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgentRefs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		apiKey, err := config.GetAPIKey()
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		agentID := args[0]
-		client := client.NewClient(apiKey)
-
-		conversation, err := client.GetAgentConversation(agentID)
+		format, file, err := exportOptions(cmd)
 		if err != nil {
-			fmt.Printf("❌ Error getting agent conversation: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("💬 Conversation History for Agent: %s\n", conversation.ID)
-		fmt.Printf("═══════════════════════════════════════\n\n")
+		client := newClient(apiKey)
+		defer printCacheNotice(client)
+		agentID := resolveAgentID(cmd.Context(), client, args[0])
+
+		conversation, err := client.GetAgentConversationContext(cmd.Context(), agentID)
+		if err != nil {
+			exitWithError("Error getting agent conversation", err)
+		}
+
+		if format != "" {
+			agent, err := client.GetAgentStatusContext(cmd.Context(), agentID)
+			if err != nil {
+				exitWithError("Error getting agent status", err)
+			}
+
+			doc := export.Document{Agent: *agent, Conversation: *conversation, ExportedAt: time.Now()}
+			if err := writeExport(file, format, doc); err != nil {
+				printError("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			if file != "" && file != "-" {
+				fmt.Printf(decorated("✅ Exported %d messages of agent %s to %s\n"), len(conversation.Messages), agentID, file)
+			}
+			return
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(conversation, conversationTable(conversation))
+			return
+		}
+
+		out := textOutput()
+		fmt.Fprintf(out, decorated("💬 Conversation History for Agent: %s\n"), conversation.ID)
+		fmt.Fprintf(out, "═══════════════════════════════════════\n\n")
 
 		if len(conversation.Messages) == 0 {
-			fmt.Println("📭 No messages found in this conversation.")
+			fmt.Fprintln(out, decorated("📭 No messages found in this conversation."))
 			return
 		}
 
 		for i, message := range conversation.Messages {
 			emoji := getMessageTypeEmoji(message.Type)
-			fmt.Printf("%s Message %d (ID: %s)\n", emoji, i+1, message.ID)
-			fmt.Printf("─────────────────────────────────\n")
-			fmt.Printf("%s\n", message.Text)
+			fmt.Fprintf(out, decorated(emoji+" Message %d (ID: %s)\n"), i+1, message.ID)
+			fmt.Fprintf(out, "─────────────────────────────────\n")
+			fmt.Fprintf(out, "%s\n", message.Text)
 
 			if i < len(conversation.Messages)-1 {
-				fmt.Println()
+				fmt.Fprintln(out)
 			}
 		}
 	},
 }
 
+// exportOptions returns the export format and file selected with --export
+// and -o, or an empty format if the conversation is not exported
+func exportOptions(cmd *cobra.Command) (export.Format, string, error) {
+	value, _ := cmd.Flags().GetString("export")
+	file, _ := cmd.Flags().GetString("output-file")
+
+	var format export.Format
+	switch {
+	case value != "":
+		var err error
+		if format, err = export.ParseFormat(value); err != nil {
+			return "", "", err
+		}
+	case file != "" && file != "-":
+		var ok bool
+		if format, ok = export.FormatForFile(file); !ok {
+			return "", "", fmt.Errorf("cannot tell the export format from %q, pass --export", file)
+		}
+	case file != "":
+		return "", "", fmt.Errorf("pass --export to write the conversation to stdout")
+	default:
+		return "", "", nil
+	}
+
+	if !outputOpts.IsText() {
+		return "", "", fmt.Errorf("--export cannot be combined with --output")
+	}
+	return format, file, nil
+}
+
+// writeExport writes doc to file, or to stdout if file is empty or "-"
+func writeExport(file string, format export.Format, doc export.Document) error {
+	if file == "" || file == "-" {
+		return export.Write(os.Stdout, format, doc)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("error creating export file: %w", err)
+	}
+	if err := export.Write(f, format, doc); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing export file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing export file: %w", err)
+	}
+	return nil
+}
+
 func getMessageTypeEmoji(messageType string) string {
 	switch messageType {
 	case "user_message":
@@ -70,4 +167,12 @@ func getMessageTypeEmoji(messageType string) string {
 
 func init() {
 	rootCmd.AddCommand(conversationCmd)
+
+	// Add flags
+	conversationCmd.Flags().String("export", "", "Export the conversation as a document: md, html, json or txt")
+	conversationCmd.Flags().StringP("output-file", "o", "", "Write the export to this file instead of stdout")
+
+	_ = conversationCmd.RegisterFlagCompletionFunc("export", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return export.Formats, cobra.ShellCompDirectiveNoFileComp
+	})
 }
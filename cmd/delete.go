@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete <agent-id...>",
+	Short: "Permanently delete one or more background agents",
+	Long: `Permanently delete one or more background agents.
+
+Deleted agents and their conversations cannot be recovered. You will be
+asked for confirmation unless --yes is given.
+
+` + agentRefHelp + `
+
+Examples:
+  cursor-cli delete bc_abc123
+  cursor-cli delete bc_abc123 bc_def456 --yes`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAgentRefs(-1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiKey, err := config.GetAPIKey()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		apiClient := newClient(apiKey)
+		agentIDs := make([]string, len(args))
+		for i, ref := range args {
+			agentIDs[i] = resolveAgentID(cmd.Context(), apiClient, ref)
+		}
+
+		question := fmt.Sprintf("Permanently delete agent %s?", agentIDs[0])
+		if len(agentIDs) > 1 {
+			question = fmt.Sprintf("Permanently delete %d agents (%s)?", len(agentIDs), strings.Join(agentIDs, ", "))
+		}
+		if !yes && !confirm(question) {
+			fmt.Fprintln(messageOutput(), "🚫 Aborted.")
+			return
+		}
+
+		var lastErr error
+		deleted := []*client.DeleteAgentResponse{}
+		var deletedIDs []string
+		for _, agentID := range agentIDs {
+			response, err := apiClient.DeleteAgentContext(cmd.Context(), agentID)
+			if err != nil {
+				fmt.Fprintf(messageOutput(), "❌ Error deleting agent %s: %v\n", agentID, err)
+				lastErr = err
+				continue
+			}
+			deleted = append(deleted, response)
+			deletedIDs = append(deletedIDs, response.ID)
+			if outputOpts.IsText() {
+				fmt.Printf(decorated("🗑️  Deleted agent %s\n"), agentID)
+			}
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(deleted, idTable(deletedIDs...))
+		}
+
+		if lastErr != nil {
+			printErrorHint(lastErr)
+			os.Exit(exitCodeFor(lastErr))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+
+	// Add flags
+	deleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
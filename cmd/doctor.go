@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/muesli/termenv"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Results of a doctor check
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// maxClockSkew is how far the local clock may drift from the API server's
+// before doctor reports it
+const maxClockSkew = time.Minute
+
+// doctorCheck is the outcome of one diagnostic
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose setup and connectivity problems",
+	Long: `Run a series of checks on your setup and print a pass/fail report:
+
+- the config file location, permissions and settings
+- where the API key comes from (--api-key, CURSOR_API_KEY or the profile)
+- DNS resolution and a TLS connection to the API base URL
+- authentication against the API with your key
+- the skew between your clock and the API server's
+- whether the terminal supports the TUI
+
+The command exits with status 1 if any check fails. Use --output json for
+a machine-readable report.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		var checks []doctorCheck
+		add := func(name, status, format string, a ...interface{}) {
+			checks = append(checks, doctorCheck{Name: name, Status: status, Message: fmt.Sprintf(format, a...)})
+		}
+
+		checkConfigFile(add)
+		apiKey := checkAPIKey(cmd, add)
+
+		apiClient, err := client.New(apiKey, clientOptions()...)
+		if err != nil {
+			add("client", checkFail, "invalid network settings: %v", err)
+		} else {
+			serverDate := checkConnection(ctx, apiClient, add)
+			checkAuth(ctx, apiClient, apiKey, add)
+			checkClockSkew(serverDate, add)
+		}
+
+		checkTerminal(add)
+
+		failed := false
+		for _, check := range checks {
+			if check.Status == checkFail {
+				failed = true
+			}
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(checks, doctorTable(checks))
+		} else {
+			out := textOutput()
+			for _, check := range checks {
+				fmt.Fprintf(out, decorated(checkIcon(check.Status)+" %-10s %s\n"), check.Name, check.Message)
+			}
+			fmt.Fprintln(out)
+			if failed {
+				fmt.Fprintln(out, decorated("❌ Some checks failed"))
+			} else {
+				fmt.Fprintln(out, decorated("✅ Everything looks good"))
+			}
+		}
+
+		if failed {
+			os.Exit(exitError)
+		}
+	},
+}
+
+// checkConfigFile reports where the config file is, whether only the user
+// can read it and whether its settings are valid
+func checkConfigFile(add func(name, status, format string, a ...interface{})) {
+	path, err := config.Path()
+	if err != nil {
+		add("config", checkFail, "cannot determine config file location: %v", err)
+		return
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		add("config", checkWarn, "%s does not exist; run 'cursor-cli init' to create it", path)
+		return
+	}
+	if err != nil {
+		add("config", checkFail, "cannot read %s: %v", path, err)
+		return
+	}
+
+	if mode := info.Mode().Perm(); runtime.GOOS != "windows" && mode&0o077 != 0 {
+		add("config", checkWarn, "%s is readable by other users (mode %04o); run 'chmod 600 %s'", path, mode, path)
+	} else {
+		add("config", checkPass, "%s (profile %q)", path, config.ActiveProfile())
+	}
+
+	if errs := config.ValidateFile(); len(errs) > 0 {
+		for _, err := range errs {
+			add("settings", checkFail, "%v", err)
+		}
+	} else {
+		add("settings", checkPass, "all settings are valid")
+	}
+}
+
+// checkAPIKey reports which source the API key is taken from, following
+// the precedence of config.GetAPIKey, and returns the key
+func checkAPIKey(cmd *cobra.Command, add func(name, status, format string, a ...interface{})) string {
+	apiKey, err := config.GetAPIKey()
+	if err != nil {
+		add("api key", checkFail, "%v", err)
+		return ""
+	}
+
+	// GetAPIKey prefers an override, then the profile's api_key_ref, then
+	// api_key wherever the config file sets it
+	r, _ := config.Resolve("api_key")
+	ref := config.ProfileString("api_key_ref")
+	var source string
+	switch {
+	case r.Source == config.SourceFlag || r.Source == config.SourceEnv:
+		source = fmt.Sprintf("%s %s", r.Source, r.Origin)
+		if ref != "" || config.ProfileString("api_key") != "" {
+			source += fmt.Sprintf(", overriding the key of profile %q", config.ActiveProfile())
+		}
+	case ref != "":
+		source = fmt.Sprintf("profile %q (%s)", config.ActiveProfile(), ref)
+	case r.Source == config.SourceProfile:
+		source = fmt.Sprintf("profile %q (plaintext)", r.Origin)
+	default:
+		source = fmt.Sprintf("%s %s (plaintext)", r.Source, r.Origin)
+	}
+
+	add("api key", checkPass, "using key %s from %s", config.Redact(apiKey), source)
+	return apiKey
+}
+
+// checkConnection resolves the API host and makes an unauthenticated
+// request to it, reporting DNS and TLS problems. It returns the server's
+// Date header, if any.
+func checkConnection(ctx context.Context, apiClient *client.Client, add func(name, status, format string, a ...interface{})) time.Time {
+	u, err := url.Parse(apiClient.BaseURL)
+	if err != nil {
+		add("dns", checkFail, "invalid base URL %q: %v", apiClient.BaseURL, err)
+		return time.Time{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+	switch {
+	case err != nil && config.GetString("proxy") != "":
+		add("dns", checkWarn, "cannot resolve %s locally, relying on the proxy: %v", u.Hostname(), err)
+	case err != nil:
+		add("dns", checkFail, "cannot resolve %s: %v", u.Hostname(), err)
+		return time.Time{}
+	default:
+		add("dns", checkPass, "%s resolves to %s", u.Hostname(), addrs[0])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiClient.BaseURL, nil)
+	if err != nil {
+		add("tls", checkFail, "%v", err)
+		return time.Time{}
+	}
+	resp, err := apiClient.HTTPClient.Do(req)
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			add("tls", checkFail, "certificate of %s is not trusted: %v (set ca_file for a corporate CA)", u.Host, certErr.Err)
+		} else {
+			add("tls", checkFail, "cannot connect to %s: %v", u.Host, err)
+		}
+		return time.Time{}
+	}
+	resp.Body.Close()
+
+	if resp.TLS == nil {
+		add("tls", checkWarn, "connected to %s without TLS", u.Host)
+	} else {
+		message := fmt.Sprintf("connected to %s with %s", u.Host, tls.VersionName(resp.TLS.Version))
+		if certs := resp.TLS.PeerCertificates; len(certs) > 0 {
+			message += ", certificate valid until " + certs[0].NotAfter.Format("2006-01-02")
+		}
+		if config.GetBool("insecure_skip_verify") {
+			add("tls", checkWarn, "%s, but certificate verification is disabled", message)
+		} else {
+			add("tls", checkPass, "%s", message)
+		}
+	}
+
+	date, _ := http.ParseTime(resp.Header.Get("Date"))
+	return date
+}
+
+// checkAuth calls /me with the API key
+func checkAuth(ctx context.Context, apiClient *client.Client, apiKey string, add func(name, status, format string, a ...interface{})) {
+	if apiKey == "" {
+		add("auth", checkSkip, "no API key to check")
+		return
+	}
+
+	keyInfo, err := apiClient.GetAPIKeyInfoContext(ctx)
+	switch {
+	case err == nil:
+		add("auth", checkPass, "authenticated as %s (key %q)", keyInfo.UserEmail, keyInfo.Name)
+	case client.IsUnauthorized(err):
+		add("auth", checkFail, "the API key was rejected; run 'cursor-cli init' to set a new one")
+	case client.IsForbidden(err):
+		add("auth", checkFail, "the API key lacks permission: %v", err)
+	default:
+		add("auth", checkFail, "%v", err)
+	}
+}
+
+// checkClockSkew compares the local clock with the server's Date header
+func checkClockSkew(serverDate time.Time, add func(name, status, format string, a ...interface{})) {
+	if serverDate.IsZero() {
+		add("clock", checkSkip, "server did not report its time")
+		return
+	}
+
+	skew := time.Since(serverDate).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		add("clock", checkWarn, "local clock is off by %s; timestamps and TLS checks may fail", skew)
+	} else {
+		add("clock", checkPass, "local clock is within %s of the server's", maxClockSkew)
+	}
+}
+
+// checkTerminal reports whether stdin and stdout are a terminal the TUI
+// can run in
+func checkTerminal(add func(name, status, format string, a ...interface{})) {
+	if !output.IsTerminal(os.Stdin) || !output.IsTerminal(os.Stdout) {
+		add("terminal", checkWarn, "stdin or stdout is not a terminal; the TUI needs an interactive terminal")
+		return
+	}
+	if os.Getenv("TERM") == "dumb" {
+		add("terminal", checkWarn, "TERM=dumb does not support the TUI")
+		return
+	}
+
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		add("terminal", checkWarn, "cannot determine terminal size: %v", err)
+		return
+	}
+
+	colors := map[termenv.Profile]string{
+		termenv.TrueColor: "true color",
+		termenv.ANSI256:   "256 colors",
+		termenv.ANSI:      "16 colors",
+		termenv.Ascii:     "no colors",
+	}[termenv.NewOutput(os.Stdout).ColorProfile()]
+
+	if width < 80 || height < 24 {
+		add("terminal", checkWarn, "%dx%d with %s; the TUI needs at least 80x24", width, height, colors)
+	} else {
+		add("terminal", checkPass, "%dx%d with %s", width, height, colors)
+	}
+}
+
+// checkIcon returns the emoji shown in front of a check in the text report
+func checkIcon(status string) string {
+	switch status {
+	case checkPass:
+		return "✅"
+	case checkWarn:
+		return "⚠️ "
+	case checkFail:
+		return "❌"
+	default:
+		return "⏭️ "
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
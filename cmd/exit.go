@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+// Exit codes returned by commands, so scripts can react to specific failures
+const (
+	exitError        = 1   // Generic failure
+	exitUnauthorized = 3   // API key missing, invalid or lacking permission
+	exitNotFound     = 4   // Agent or resource does not exist
+	exitRateLimited  = 5   // Too many requests
+	exitServerError  = 6   // The API failed on its side
+	exitCancelled    = 130 // Interrupted with Ctrl+C
+
+	// Final status of agents awaited by watch and wait
+	exitAgentFailed     = 10
+	exitAgentCancelled  = 11
+	exitAgentExpired    = 12
+	exitConditionNotMet = 13  // Agent finished in a status other than the awaited one
+	exitTimeout         = 124 // Gave up waiting, as timeout(1) does
+)
+
+// exitWithError prints err prefixed with msg, adds a hint for well-known API
+// failures and exits with the matching exit code
+func exitWithError(msg string, err error) {
+	printError("❌ %s: %v\n", msg, err)
+	printErrorHint(err)
+	os.Exit(exitCodeFor(err))
+}
+
+// exitCodeFor maps an error to the exit code the CLI should return for it
+func exitCodeFor(err error) int {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return exitCancelled
+	case client.IsUnauthorized(err), client.IsForbidden(err):
+		return exitUnauthorized
+	case client.IsNotFound(err), errors.Is(err, cache.ErrNotFound):
+		return exitNotFound
+	case client.IsRateLimited(err):
+		return exitRateLimited
+	case client.IsServerError(err):
+		return exitServerError
+	default:
+		return exitError
+	}
+}
+
+// exitCodeForStatus maps the final status of an agent to an exit code
+func exitCodeForStatus(status string) int {
+	switch status {
+	case client.StatusCompleted:
+		return 0
+	case client.StatusFailed:
+		return exitAgentFailed
+	case client.StatusCancelled:
+		return exitAgentCancelled
+	case client.StatusExpired:
+		return exitAgentExpired
+	default:
+		return exitError
+	}
+}
+
+// printErrorHint prints a suggestion on how to fix well-known API failures
+func printErrorHint(err error) {
+	var urlErr *url.Error
+
+	switch {
+	case client.IsUnauthorized(err):
+		printError("💡 The API key was rejected. Check that it is correct and active, or run 'cursor-cli init' to set a new one.\n")
+	case client.IsForbidden(err):
+		printError("💡 Your API key does not have access to this resource.\n")
+	case client.IsNotFound(err):
+		printError("💡 Check the agent ID with 'cursor-cli list --all'.\n")
+	case client.IsRateLimited(err):
+		printError("💡 You are being rate limited. Wait a moment and try again.\n")
+	case client.IsServerError(err):
+		printError("💡 The Cursor API is having trouble. Try again later.\n")
+	case errors.Is(err, client.ErrOffline):
+		printError("💡 This needs the Cursor API. Run it again without --offline.\n")
+	case errors.Is(err, cache.ErrNotFound):
+		printError("💡 Only agents fetched while online are available offline. Run the command again without --offline.\n")
+	case errors.As(err, &urlErr) && !errors.Is(err, context.Canceled):
+		printError("💡 Could not reach the Cursor API. Run 'cursor-cli doctor' to diagnose network and TLS problems, or use --offline to see cached agents.\n")
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/filter"
+	"github.com/spf13/cobra"
+)
+
+// filterHelp documents the agent filter flags
+const filterHelp = `Filters compose: an agent is shown only if it matches all of them.
+  --status    one of the given statuses (repeatable or comma-separated)
+  --repo      repository glob, matched against the URL, "github.com/org/repo",
+              "org/repo" or "repo"
+  --name      case-insensitive substring of the name, or a regular
+              expression enclosed in slashes
+  --branch    glob matched against the agent's branch
+  --since     created at or after a time: a duration before now (2h, 7d,
+              2w) or a date (2024-06-01, 2024-06-01T15:04, RFC 3339)
+  --until     created before such a time`
+
+// addFilterFlags adds the agent filter flags to cmd
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("status", nil, "Only show agents with this status (repeatable or comma-separated)")
+	cmd.Flags().String("repo", "", "Only show agents on repositories matching this glob (e.g. 'org/*')")
+	cmd.Flags().String("name", "", "Only show agents whose name contains this text, or matches /regexp/")
+	cmd.Flags().String("branch", "", "Only show agents whose branch matches this glob")
+	cmd.Flags().String("since", "", "Only show agents created since a duration ago (2h, 7d) or a date")
+	cmd.Flags().String("until", "", "Only show agents created before a duration ago (2h, 7d) or a date")
+
+	_ = cmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return client.Statuses, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// parseFilterFlags builds the filter selected with the flags added by
+// addFilterFlags, showing expired agents if showExpired is set
+func parseFilterFlags(cmd *cobra.Command, showExpired bool) (filter.Filter, error) {
+	opts := filter.Options{IncludeExpired: showExpired}
+	opts.Statuses, _ = cmd.Flags().GetStringArray("status")
+	opts.Repo, _ = cmd.Flags().GetString("repo")
+	opts.Name, _ = cmd.Flags().GetString("name")
+	opts.Branch, _ = cmd.Flags().GetString("branch")
+	opts.Since, _ = cmd.Flags().GetString("since")
+	opts.Until, _ = cmd.Flags().GetString("until")
+
+	return filter.New(opts, time.Now())
+}
@@ -18,32 +18,56 @@ var followupCmd = &cobra.Command{
 This allows you to provide additional context or modify the agent's task
 while it's still running. The prompt will be added to the agent's conversation.
 
-Example:
-  cursor-cli followup bc_abc123 "Also add a section about troubleshooting"`,
-	Args: cobra.ExactArgs(2),
+Images (PNG, JPEG or GIF) can be attached with --image, up to 5 per
+follow-up and 10 MiB each.
+
+` + agentRefHelp + `
+
+Examples:
+  cursor-cli followup bc_abc123 "Also add a section about troubleshooting"
+  cursor-cli followup bc_abc123 "Match this mockup" --image mockup.png
+  cursor-cli followup @last-running "Use the existing logger"`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeAgentRefs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		apiKey, err := config.GetAPIKey()
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		agentID := args[0]
 		prompt := args[1]
+		imagePaths, _ := cmd.Flags().GetStringArray("image")
+
+		images, err := client.LoadImages(imagePaths)
+		if err != nil {
+			exitWithError("Error attaching images", err)
+		}
+		request := client.Prompt{Text: prompt, Images: images}
 
-		client := client.NewClient(apiKey)
+		client := newClient(apiKey)
+		agentID := resolveAgentID(cmd.Context(), client, args[0])
 
-		fmt.Printf("📤 Sending follow-up instruction to agent %s...\n", agentID)
+		if outputOpts.IsText() {
+			fmt.Printf(decorated("📤 Sending follow-up instruction to agent %s...\n"), agentID)
+		}
 
-		response, err := client.AddFollowup(agentID, prompt)
+		response, err := client.AddFollowupPromptContext(cmd.Context(), agentID, request)
 		if err != nil {
-			fmt.Printf("❌ Error adding follow-up: %v\n", err)
-			os.Exit(1)
+			exitWithError("Error adding follow-up", err)
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(response, idTable(response.ID))
+			return
 		}
 
-		fmt.Printf("✅ Follow-up instruction sent successfully!\n")
-		fmt.Printf("🤖 Agent ID: %s\n", response.ID)
-		fmt.Printf("💬 Instruction: %s\n", prompt)
+		fmt.Print(decorated("✅ Follow-up instruction sent successfully!\n"))
+		fmt.Printf(decorated("🤖 Agent ID: %s\n"), response.ID)
+		fmt.Printf(decorated("💬 Instruction: %s\n"), prompt)
+		if len(images) > 0 {
+			fmt.Printf(decorated("🖼️  Images: %d attached\n"), len(images))
+		}
 		fmt.Println()
 		fmt.Println("The agent will process your follow-up instruction and continue working.")
 		fmt.Printf("You can check the status with: cursor-cli status %s\n", agentID)
@@ -52,4 +76,7 @@ Example:
 
 func init() {
 	rootCmd.AddCommand(followupCmd)
+
+	// Add flags
+	followupCmd.Flags().StringArray("image", nil, "Attach a PNG, JPEG or GIF image (repeatable)")
 }
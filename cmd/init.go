@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // initCmd represents the init command
@@ -16,49 +22,166 @@ var initCmd = &cobra.Command{
 	Long: `Initialize cursor-cli by setting up your Cursor API key.
 
 You can get your API key from the Cursor Dashboard → Integrations.
-The API key will be stored in ~/.cursor-cli.yaml for future use.`,
+The API key is stored for the profile selected with --profile (or
+"default"), in the file given with --config or the default config file
+($XDG_CONFIG_HOME/cursor-cli/config.yaml). Where the key itself is kept is
+chosen with --secret-backend:
+
+  auto     the OS keyring when available, plain otherwise (default)
+  keyring  the Secret Service (GNOME Keyring, KWallet) or macOS Keychain
+  file     a file encrypted with a passphrase (set CURSOR_CLI_PASSPHRASE
+           to avoid the prompt)
+  helper   an external command set with --credential-helper, like git's
+           credential helpers
+  plain    the config file itself, readable only by you
+
+With any backend other than plain the config file only holds a reference
+to the key.
+
+When run interactively the key is read without echoing it. For CI and
+scripts, pipe it in with --api-key-stdin, or pass --non-interactive to take
+it from --api-key or CURSOR_API_KEY without ever prompting.
+
+Examples:
+  cursor-cli --profile work init
+  cursor-cli init --secret-backend file
+  cursor-cli init --secret-backend helper --credential-helper "pass-cursor"
+  echo "$CURSOR_KEY" | cursor-cli --config ./ci.yaml init --api-key-stdin --secret-backend plain
+  CURSOR_API_KEY=key_... cursor-cli init --non-interactive --skip-validation`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("🚀 Welcome to cursor-cli setup!")
-		fmt.Println()
-		fmt.Println("To get started, you'll need a Cursor API key.")
-		fmt.Println("You can create one at: https://cursor.com/dashboard")
-		fmt.Println("Navigate to: Dashboard → Integrations → Create API Key")
-		fmt.Println()
+		backend, _ := cmd.Flags().GetString("secret-backend")
+		helper, _ := cmd.Flags().GetString("credential-helper")
+		fromStdin, _ := cmd.Flags().GetBool("api-key-stdin")
+		nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+		skipValidation, _ := cmd.Flags().GetBool("skip-validation")
 
-		fmt.Print("Please enter your Cursor API key: ")
-		var apiKey string
-		fmt.Scanln(&apiKey)
+		if helper != "" && !cmd.Flags().Changed("secret-backend") {
+			backend = config.BackendHelper
+		}
 
-		if apiKey == "" {
-			fmt.Println("❌ Error: API key cannot be empty")
-			os.Exit(1)
+		// The helper is only saved with the key, once it has been validated
+		pendingHelper := backend == config.BackendHelper && helper != ""
+		if backend != config.BackendAuto && backend != config.BackendPlain && !pendingHelper {
+			if _, err := config.Backend(backend); err != nil {
+				printError("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
 		}
 
-		// Test the API key by making a request to the API key info endpoint
-		fmt.Println("🔍 Validating API key...")
-		client := client.NewClient(apiKey)
-		keyInfo, err := client.GetAPIKeyInfo()
+		if !fromStdin && !nonInteractive {
+			fmt.Println(decorated("🚀 Welcome to cursor-cli setup!"))
+			fmt.Println()
+			fmt.Println("To get started, you'll need a Cursor API key.")
+			fmt.Println("You can create one at: https://cursor.com/dashboard")
+			fmt.Println("Navigate to: Dashboard → Integrations → Create API Key")
+			fmt.Println()
+		}
+
+		if fromStdin || nonInteractive {
+			config.Interactive = false
+		}
+
+		apiKey, err := readInitAPIKey(fromStdin, nonInteractive)
 		if err != nil {
-			fmt.Printf("❌ Error: Invalid API key or network error: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
+		// Test the API key by making a request to the API key info endpoint
+		var keyInfo *client.APIKeyInfo
+		if !skipValidation {
+			fmt.Println(decorated("🔍 Validating API key..."))
+			apiClient := newClient(apiKey)
+			keyInfo, err = apiClient.GetAPIKeyInfoContext(cmd.Context())
+			if err != nil {
+				exitWithError("Error validating API key", err)
+			}
+		}
+
 		// Save the API key to config
-		if err := config.SaveAPIKey(apiKey); err != nil {
-			fmt.Printf("❌ Error saving API key: %v\n", err)
+		backend, err = config.SaveAPIKeyWithHelper(apiKey, backend, helper)
+		if err != nil {
+			printError("❌ Error saving API key: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("✅ API key validated and saved successfully!")
-		fmt.Printf("📧 Authenticated as: %s\n", keyInfo.UserEmail)
-		fmt.Printf("🔑 Key ID: %s\n", keyInfo.ID)
-		fmt.Printf("📅 Created: %s\n", keyInfo.CreatedAt.Format("2006-01-02 15:04:05"))
+		path, _ := config.Path()
+		if keyInfo == nil {
+			fmt.Printf(decorated("✅ API key saved without validation to profile %q (%s) in %s\n"), config.ActiveProfile(), backend, path)
+		} else {
+			fmt.Printf(decorated("✅ API key validated and saved to profile %q (%s) in %s!\n"), config.ActiveProfile(), backend, path)
+			fmt.Printf(decorated("📧 Authenticated as: %s\n"), keyInfo.UserEmail)
+			fmt.Printf(decorated("🔑 Key ID: %s\n"), keyInfo.ID)
+			fmt.Printf(decorated("📅 Created: %s\n"), keyInfo.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
+		if fromStdin || nonInteractive {
+			return
+		}
+
+		if backend == config.BackendPlain {
+			fmt.Println(decorated("💡 The key is stored in plaintext; use --secret-backend keyring, file or helper to keep it out of the config file"))
+		}
 		fmt.Println()
-		fmt.Println("🎉 cursor-cli is ready to use!")
+		fmt.Println(decorated("🎉 cursor-cli is ready to use!"))
 		fmt.Println("Try running: cursor-cli list")
 	},
 }
 
+// readInitAPIKey returns the API key init should store. With fromStdin it
+// is read from stdin; in non-interactive mode it comes from --api-key or
+// CURSOR_API_KEY. Otherwise the user is prompted, without echo when stdin
+// is a terminal.
+func readInitAPIKey(fromStdin, nonInteractive bool) (string, error) {
+	var apiKey string
+
+	switch {
+	case fromStdin:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("error reading API key from stdin: %w", err)
+		}
+		apiKey = string(data)
+	case nonInteractive:
+		r, _ := config.Resolve("api_key")
+		if r.Source == config.SourceFlag || r.Source == config.SourceEnv {
+			apiKey = fmt.Sprint(r.Value)
+		}
+		if apiKey == "" {
+			return "", errors.New("--non-interactive needs the API key from --api-key-stdin, --api-key or CURSOR_API_KEY")
+		}
+	case output.IsTerminal(os.Stdin):
+		fmt.Print("Please enter your Cursor API key: ")
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("error reading API key: %w", err)
+		}
+		apiKey = string(data)
+	default:
+		fmt.Print("Please enter your Cursor API key: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		fmt.Println()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("error reading API key: %w", err)
+		}
+		apiKey = line
+	}
+
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return "", errors.New("API key cannot be empty")
+	}
+	return apiKey, nil
+}
+
 func init() {
 	rootCmd.AddCommand(initCmd)
+
+	// Add flags
+	initCmd.Flags().String("secret-backend", config.BackendAuto, "Where to store the API key: auto, keyring, file, helper or plain")
+	initCmd.Flags().String("credential-helper", "", "Command used to store and look up the API key (implies --secret-backend helper)")
+	initCmd.Flags().Bool("api-key-stdin", false, "Read the API key from stdin")
+	initCmd.Flags().Bool("non-interactive", false, "Never prompt; take the API key from --api-key-stdin, --api-key or CURSOR_API_KEY")
+	initCmd.Flags().Bool("skip-validation", false, "Save the API key without checking it against the API")
 }
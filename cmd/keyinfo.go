@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -20,27 +19,32 @@ the key ID, name, creation date, and associated user email.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		apiKey, err := config.GetAPIKey()
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		client := client.NewClient(apiKey)
+		client := newClient(apiKey)
 
-		keyInfo, err := client.GetAPIKeyInfo()
+		keyInfo, err := client.GetAPIKeyInfoContext(cmd.Context())
 		if err != nil {
-			fmt.Printf("❌ Error getting API key info: %v\n", err)
-			os.Exit(1)
+			exitWithError("Error getting API key info", err)
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(keyInfo, keyInfoTable(keyInfo))
+			return
 		}
 
-		fmt.Printf("🔑 API Key Information\n")
-		fmt.Printf("═════════════════════\n\n")
+		out := textOutput()
+		fmt.Fprint(out, decorated("🔑 API Key Information\n"))
+		fmt.Fprintf(out, "═════════════════════\n\n")
 
-		fmt.Printf("📋 ID: %s\n", keyInfo.ID)
-		fmt.Printf("📝 Name: %s\n", keyInfo.Name)
-		fmt.Printf("📧 User Email: %s\n", keyInfo.UserEmail)
-		fmt.Printf("📅 Created: %s\n", keyInfo.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(out, decorated("📋 ID: %s\n"), keyInfo.ID)
+		fmt.Fprintf(out, decorated("📝 Name: %s\n"), keyInfo.Name)
+		fmt.Fprintf(out, decorated("📧 User Email: %s\n"), keyInfo.UserEmail)
+		fmt.Fprintf(out, decorated("📅 Created: %s\n"), keyInfo.CreatedAt.Format("2006-01-02 15:04:05"))
 
-		fmt.Println("\n✅ API key is valid and active!")
+		fmt.Fprintln(out, decorated("\n✅ API key is valid and active!"))
 	},
 }
 
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// launchCmd represents the launch command
+var launchCmd = &cobra.Command{
+	Use:   "launch [prompt]",
+	Short: "Launch a new background agent",
+	Long: `Start a new background agent working on a repository.
+
+The prompt can be passed as an argument, read from a file with --prompt-file,
+or piped in through stdin. On success the ID of the new agent is printed.
+Images (PNG, JPEG or GIF) can be attached with --image, up to 5 per prompt
+and 10 MiB each.
+
+Examples:
+  cursor-cli launch --repo https://github.com/org/repo "Add a README"
+  cursor-cli launch --repo https://github.com/org/repo --prompt-file task.md
+  echo "Fix the flaky tests" | cursor-cli launch --repo https://github.com/org/repo`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiKey, err := config.GetAPIKey()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		repository, _ := cmd.Flags().GetString("repo")
+		ref, _ := cmd.Flags().GetString("ref")
+		branch, _ := cmd.Flags().GetString("branch")
+		autoPR, _ := cmd.Flags().GetBool("auto-pr")
+		model, _ := cmd.Flags().GetString("model")
+		promptFile, _ := cmd.Flags().GetString("prompt-file")
+		idempotencyKey, _ := cmd.Flags().GetString("idempotency-key")
+		imagePaths, _ := cmd.Flags().GetStringArray("image")
+
+		// Fall back to the configured defaults, e.g. of the active profile
+		if repository == "" {
+			repository = config.GetString("repository")
+		}
+		if ref == "" {
+			ref = config.GetString("ref")
+		}
+		if model == "" {
+			model = config.GetString("model")
+		}
+		if !cmd.Flags().Changed("auto-pr") && config.IsSet("auto_pr") {
+			autoPR = config.GetBool("auto_pr")
+		}
+
+		if repository == "" {
+			printError("❌ Error: --repo is required (or set a default repository in your profile)\n")
+			os.Exit(1)
+		}
+
+		prompt, err := readLaunchPrompt(args, promptFile)
+		if err != nil {
+			exitWithError("Error reading prompt", err)
+		}
+
+		images, err := client.LoadImages(imagePaths)
+		if err != nil {
+			exitWithError("Error attaching images", err)
+		}
+
+		request := client.LaunchAgentRequest{
+			Prompt: client.Prompt{Text: prompt, Images: images},
+			Source: client.Source{
+				Repository: repository,
+				Ref:        ref,
+			},
+			Model: model,
+		}
+		if branch != "" || autoPR {
+			request.Target = &client.Target{
+				BranchName:   branch,
+				AutoCreatePr: autoPR,
+			}
+		}
+
+		ctx := cmd.Context()
+		if idempotencyKey != "" {
+			ctx = client.WithIdempotencyKey(ctx, idempotencyKey)
+		}
+
+		client := newClient(apiKey)
+
+		if outputOpts.IsText() {
+			fmt.Printf(decorated("🚀 Launching background agent on %s...\n"), repository)
+		}
+
+		agent, err := client.LaunchAgentContext(ctx, request)
+		if err != nil {
+			exitWithError("Error launching agent", err)
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(agent, agentTable(agent))
+			return
+		}
+
+		fmt.Print(decorated("✅ Agent launched successfully!\n"))
+		fmt.Printf(decorated("🤖 Agent ID: %s\n"), agent.ID)
+		if agent.Target.URL != "" {
+			fmt.Printf(decorated("🔗 Agent URL: %s\n"), agent.Target.URL)
+		}
+		fmt.Println()
+		fmt.Printf("You can check the status with: cursor-cli status %s\n", agent.ID)
+	},
+}
+
+// readLaunchPrompt resolves the prompt from the argument, the prompt file
+// ("-" meaning stdin) or stdin, in that order
+func readLaunchPrompt(args []string, promptFile string) (string, error) {
+	if len(args) > 0 && promptFile != "" {
+		return "", fmt.Errorf("pass the prompt either as an argument or with --prompt-file, not both")
+	}
+
+	var prompt string
+	switch {
+	case len(args) > 0:
+		prompt = args[0]
+	case promptFile != "" && promptFile != "-":
+		data, err := os.ReadFile(promptFile)
+		if err != nil {
+			return "", err
+		}
+		prompt = string(data)
+	default:
+		if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+			fmt.Println(decorated("📝 Enter the prompt for the agent (Ctrl+D to finish):"))
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		prompt = string(data)
+	}
+
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return "", fmt.Errorf("prompt cannot be empty")
+	}
+
+	return prompt, nil
+}
+
+func init() {
+	rootCmd.AddCommand(launchCmd)
+
+	// Add flags
+	launchCmd.Flags().StringP("repo", "r", "", "Repository URL the agent should work on (default from profile)")
+	launchCmd.Flags().String("ref", "", "Git ref (branch, tag or commit) to start from")
+	launchCmd.Flags().StringP("branch", "b", "", "Name of the branch the agent should create")
+	launchCmd.Flags().Bool("auto-pr", false, "Automatically open a pull request when the agent finishes")
+	launchCmd.Flags().StringP("model", "m", "", "Model the agent should use")
+	launchCmd.Flags().StringP("prompt-file", "f", "", "Read the prompt from a file ('-' for stdin)")
+	launchCmd.Flags().StringArray("image", nil, "Attach a PNG, JPEG or GIF image (repeatable)")
+	launchCmd.Flags().String("idempotency-key", "", "Idempotency key so the launch can be safely retried")
+}
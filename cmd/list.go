@@ -3,11 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"unicode/utf8"
 
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -18,88 +21,380 @@ var listCmd = &cobra.Command{
 	Long: `Retrieve and display a list of all background agents associated with your account.
 	
 This command shows the ID, name, status, and creation time of each agent.
-By default, expired agents are filtered out and only running/finished agents are shown.`,
+By default, expired agents are filtered out and only running/finished agents are shown.
+
+` + filterHelp + `
+
+Filters are applied across all pages: when any is given, list follows
+pagination until every agent has been checked (or --max agents matched).
+
+--sort orders the fetched agents by created, name, status or repo, in
+descending order with a leading "-" (e.g. -created for newest first).
+--columns picks the table columns from id, name, status, repo, ref,
+branch, url, pr, summary and created; --wide adds the branch and pull
+request URL to the default columns. With --output csv, --columns also
+selects the csv columns.
+
+Examples:
+  cursor-cli list --status running --status creating
+  cursor-cli list --repo 'my-org/*' --since 7d
+  cursor-cli list --name '/^fix-/' --branch 'cursor/*'
+  cursor-cli list --status failed --since 2024-06-01 --until 2024-07-01
+  cursor-cli list --sort -created --columns id,name,status,branch,pr
+  cursor-cli list --all-pages --no-headers --columns id | xargs -n1 cursor-cli status`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		apiKey, err := config.GetAPIKey()
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
 		limit, _ := cmd.Flags().GetInt("limit")
 		cursor, _ := cmd.Flags().GetString("cursor")
 		showAll, _ := cmd.Flags().GetBool("all")
+		allPages, _ := cmd.Flags().GetBool("all-pages")
+		max, _ := cmd.Flags().GetInt("max")
+		sortKey, _ := cmd.Flags().GetString("sort")
+		columnList, _ := cmd.Flags().GetString("columns")
+		wide, _ := cmd.Flags().GetBool("wide")
+		noHeaders, _ := cmd.Flags().GetBool("no-headers")
+
+		if err := validateSortKey(sortKey); err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if columnList == "" {
+			columnList = defaultListColumns
+			if wide {
+				columnList = wideListColumns
+			}
+		}
+		columns, err := parseAgentColumns(columnList)
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
 
-		client := client.NewClient(apiKey)
-		response, err := client.ListAgents(limit, cursor)
+		agentFilter, err := parseFilterFlags(cmd, showAll)
 		if err != nil {
-			fmt.Printf("❌ Error listing agents: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Filter agents if not showing all
-		filteredAgents := response.Agents
-		if !showAll {
-			filteredAgents = filterActiveAgents(response.Agents)
+		// Narrowing filters are applied across all pages, fetched in as
+		// few requests as possible
+		if agentFilter.Narrowed() {
+			allPages = true
+			if !cmd.Flags().Changed("limit") {
+				limit = client.MaxPageSize
+			}
+		}
+
+		apiClient := newClient(apiKey)
+		defer printCacheNotice(apiClient)
+		pager := apiClient.NewPager(limit, cursor)
+
+		// Fetch a single page, or keep following the cursor with --all-pages,
+		// --max or filters until enough agents are collected
+		var filteredAgents []client.Agent
+		truncated := false
+		for !pager.Done() {
+			if max > 0 && !agentFilter.Narrowed() {
+				// End the last page where --max does, so that its cursor
+				// continues right after the agents shown
+				pager.SetPageSize(min(limit, max-len(filteredAgents)))
+			}
+
+			page, err := pager.Next(cmd.Context())
+			if err != nil {
+				exitWithError("Error listing agents", err)
+			}
+
+			matched := agentFilter.Apply(page)
+			if max > 0 && len(filteredAgents)+len(matched) > max {
+				matched = matched[:max-len(filteredAgents)]
+				truncated = true
+			}
+			filteredAgents = append(filteredAgents, matched...)
+
+			if max > 0 && len(filteredAgents) >= max {
+				break
+			}
+			if !allPages && max <= 0 {
+				break
+			}
+		}
+
+		// The cursor of a page cut short by --max would skip the rest of it
+		nextCursor := pager.Cursor()
+		if truncated {
+			nextCursor = ""
+		}
+
+		sortAgents(filteredAgents, sortKey)
+
+		if !outputOpts.IsText() {
+			table := agentsTable(filteredAgents)
+			if cmd.Flags().Changed("columns") {
+				table = columnsTable(filteredAgents, columns)
+			}
+			if noHeaders {
+				table.Header = nil
+			}
+			writeOutput(filteredAgents, table)
+			if nextCursor != "" {
+				fmt.Fprintf(os.Stderr, "More results available. Use --cursor=%s to get the next page.\n", nextCursor)
+			}
+			return
+		}
+
+		out := textOutput()
+
+		// Without headers only the rows are printed, for use in scripts
+		if noHeaders {
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			for _, agent := range filteredAgents {
+				fmt.Fprintln(w, strings.Join(columnValues(agent, columns), "\t"))
+			}
+			w.Flush()
+			if nextCursor != "" {
+				fmt.Fprintf(os.Stderr, "More results available. Use --cursor=%s to get the next page.\n", nextCursor)
+			}
+			return
 		}
 
 		if len(filteredAgents) == 0 {
-			if showAll {
-				fmt.Println("📭 No background agents found.")
+			if agentFilter.Narrowed() {
+				fmt.Fprintln(out, decorated("📭 No background agents match the filters."))
+			} else if showAll {
+				fmt.Fprintln(out, decorated("📭 No background agents found."))
 			} else {
-				fmt.Println("📭 No active background agents found.")
-				fmt.Println("💡 Use --all flag to see expired agents as well.")
+				fmt.Fprintln(out, decorated("📭 No active background agents found."))
+				fmt.Fprintln(out, decorated("💡 Use --all flag to see expired agents as well."))
 			}
 			return
 		}
 
 		statusText := "all"
-		if !showAll {
+		if agentFilter.Narrowed() {
+			statusText = "matching"
+		} else if !showAll {
 			statusText = "active"
 		}
-		fmt.Printf("📋 Found %d %s background agents:\n\n", len(filteredAgents), statusText)
+		fmt.Fprintf(out, decorated("📋 Found %d %s background agents:\n\n"), len(filteredAgents), statusText)
 
 		// Create a tab writer for formatted output
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "ID\tNAME\tSTATUS\tREPOSITORY\tCREATED")
-		fmt.Fprintln(w, "──\t────\t──────\t──────────\t───────")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		var headers, rules []string
+		for _, column := range columns {
+			headers = append(headers, column.header)
+			rules = append(rules, strings.Repeat("─", utf8.RuneCountInString(column.header)))
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+		fmt.Fprintln(w, strings.Join(rules, "\t"))
 
 		for _, agent := range filteredAgents {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-				agent.ID,
-				agent.Name,
-				agent.Status,
-				agent.Source.Repository,
-				agent.CreatedAt.Format("2006-01-02 15:04"),
-			)
+			fmt.Fprintln(w, strings.Join(columnValues(agent, columns), "\t"))
 		}
 
 		w.Flush()
 
-		if response.NextCursor != "" {
-			fmt.Printf("\n🔗 More results available. Use --cursor=%s to get the next page.\n", response.NextCursor)
+		if nextCursor != "" {
+			fmt.Fprintf(out, decorated("\n🔗 More results available. Use --cursor=%s to get the next page, or --all-pages to fetch everything.\n"), nextCursor)
 		}
 	},
 }
 
-// filterActiveAgents filters out expired agents, keeping only running/finished ones
-func filterActiveAgents(agents []client.Agent) []client.Agent {
-	var filtered []client.Agent
-	for _, agent := range agents {
-		status := strings.ToUpper(agent.Status)
-		// Include running, completed, failed, cancelled - exclude expired
-		if status != "EXPIRED" {
-			filtered = append(filtered, agent)
+// Columns of the list table shown by default and with --wide
+const (
+	defaultListColumns = "id,name,status,repo,created"
+	wideListColumns    = "id,name,status,repo,branch,pr,created"
+)
+
+// agentColumn is a column the list table can show
+type agentColumn struct {
+	name   string // Name used with --columns
+	header string // Header of the text table
+	csv    string // Header of the column in agentsTable
+	value  func(agent client.Agent) string
+}
+
+// agentColumns lists the columns accepted by --columns
+var agentColumns = []agentColumn{
+	{"id", "ID", "id", func(a client.Agent) string { return a.ID }},
+	{"name", "NAME", "name", func(a client.Agent) string { return a.Name }},
+	{"status", "STATUS", "status", func(a client.Agent) string { return a.Status }},
+	{"repo", "REPOSITORY", "repository", func(a client.Agent) string { return a.Source.Repository }},
+	{"ref", "REF", "ref", func(a client.Agent) string { return a.Source.Ref }},
+	{"branch", "BRANCH", "branch", func(a client.Agent) string { return a.Target.BranchName }},
+	{"url", "URL", "url", func(a client.Agent) string { return a.Target.URL }},
+	{"pr", "PR", "pr_url", func(a client.Agent) string { return a.Target.PrURL }},
+	{"summary", "SUMMARY", "summary", func(a client.Agent) string { return a.Summary }},
+	{"created", "CREATED", "created_at", func(a client.Agent) string { return a.CreatedAt.Format("2006-01-02 15:04") }},
+}
+
+// agentColumnNames returns the names accepted by --columns
+func agentColumnNames() []string {
+	var names []string
+	for _, column := range agentColumns {
+		names = append(names, column.name)
+	}
+	return names
+}
+
+// parseAgentColumns parses a comma-separated --columns value
+func parseAgentColumns(value string) ([]agentColumn, error) {
+	var columns []agentColumn
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for _, column := range agentColumns {
+			if column.name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q, expected some of %s", name, strings.Join(agentColumnNames(), ", "))
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("--columns needs at least one column")
+	}
+	return columns, nil
+}
+
+// columnValues returns the cells of agent's row in the text table. Line
+// breaks are flattened and long summaries shortened to keep one agent per
+// line.
+func columnValues(agent client.Agent, columns []agentColumn) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		value := strings.Join(strings.Fields(column.value(agent)), " ")
+		if column.name == "summary" && utf8.RuneCountInString(value) > maxSummaryWidth {
+			value = string([]rune(value)[:maxSummaryWidth-1]) + "…"
+		}
+		if value == "" {
+			value = "-"
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// maxSummaryWidth is how many characters of the summary the text table
+// shows
+const maxSummaryWidth = 60
+
+// columnsTable returns the csv form of agents restricted to columns
+func columnsTable(agents []client.Agent, columns []agentColumn) output.Table {
+	full := agentsTable(agents)
+
+	var indexes []int
+	var table output.Table
+	for _, column := range columns {
+		for i, header := range full.Header {
+			if header == column.csv {
+				indexes = append(indexes, i)
+				table.Header = append(table.Header, header)
+			}
+		}
+	}
+	for _, row := range full.Rows {
+		projected := make([]string, len(indexes))
+		for i, index := range indexes {
+			projected[i] = row[index]
+		}
+		table.Rows = append(table.Rows, projected)
+	}
+	return table
+}
+
+// sortKeys lists the fields --sort accepts
+var sortKeys = []string{"created", "name", "status", "repo"}
+
+// validateSortKey checks a --sort value
+func validateSortKey(key string) error {
+	if key == "" {
+		return nil
+	}
+	name := strings.TrimPrefix(strings.ToLower(key), "-")
+	for _, k := range sortKeys {
+		if k == name {
+			return nil
 		}
 	}
-	return filtered
+	return fmt.Errorf("unknown sort key %q, expected one of %s (prefix with - to reverse)", key, strings.Join(sortKeys, ", "))
+}
+
+// sortAgents sorts agents in place by a --sort key, keeping API order for
+// agents that compare equal
+func sortAgents(agents []client.Agent, key string) {
+	if key == "" {
+		return
+	}
+	key = strings.ToLower(key)
+	descending := strings.HasPrefix(key, "-")
+
+	var less func(a, b client.Agent) bool
+	switch strings.TrimPrefix(key, "-") {
+	case "created":
+		less = func(a, b client.Agent) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "name":
+		less = func(a, b client.Agent) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "status":
+		less = func(a, b client.Agent) bool { return a.Status < b.Status }
+	case "repo":
+		less = func(a, b client.Agent) bool { return a.Source.Repository < b.Source.Repository }
+	default:
+		return
+	}
+
+	sort.SliceStable(agents, func(i, j int) bool {
+		if descending {
+			return less(agents[j], agents[i])
+		}
+		return less(agents[i], agents[j])
+	})
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 
 	// Add flags
-	listCmd.Flags().IntP("limit", "l", 20, "Number of agents to return (1-100)")
+	listCmd.Flags().IntP("limit", "l", 20, "Number of agents to fetch per page (1-100)")
 	listCmd.Flags().StringP("cursor", "c", "", "Pagination cursor from previous response")
 	listCmd.Flags().BoolP("all", "a", false, "Show all agents including expired ones")
+	listCmd.Flags().Bool("all-pages", false, "Follow pagination and list agents from every page")
+	listCmd.Flags().Int("max", 0, "Stop after this many agents, following pagination as needed")
+	listCmd.Flags().String("sort", "", "Sort by created, name, status or repo; prefix with - for descending order")
+	listCmd.Flags().String("columns", "", "Comma-separated columns to show: "+strings.Join(agentColumnNames(), ", "))
+	listCmd.Flags().Bool("wide", false, "Also show the branch and pull request URL")
+	listCmd.Flags().Bool("no-headers", false, "Print only the agent rows, without headers or summary")
+	listCmd.MarkFlagsMutuallyExclusive("columns", "wide")
+	addFilterFlags(listCmd)
+
+	_ = listCmd.RegisterFlagCompletionFunc("sort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var keys []string
+		for _, key := range sortKeys {
+			keys = append(keys, key, "-"+key)
+		}
+		return keys, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = listCmd.RegisterFlagCompletionFunc("columns", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Complete the column after the last comma
+		prefix := toComplete[:strings.LastIndex(toComplete, ",")+1]
+		var completions []string
+		for _, name := range agentColumnNames() {
+			completions = append(completions, prefix+name)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	})
 }
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+func TestValidateSortKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{key: ""},
+		{key: "created"},
+		{key: "-created"},
+		{key: "Name"},
+		{key: "-STATUS"},
+		{key: "repo"},
+		{key: "updated", wantErr: true},
+		{key: "--name", wantErr: true},
+		{key: "name,status", wantErr: true},
+		{key: "-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			err := validateSortKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSortKey(%q) error = %v, want error %v", tt.key, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), strings.Join(sortKeys, ", ")) {
+				t.Errorf("validateSortKey(%q) error = %q, want it to list the sort keys", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestParseAgentColumns(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "id", want: []string{"id"}},
+		{value: "ID, Name ,status", want: []string{"id", "name", "status"}},
+		{value: "repo,,created,", want: []string{"repo", "created"}},
+		{value: "created,id", want: []string{"created", "id"}},
+		{value: defaultListColumns, want: strings.Split(defaultListColumns, ",")},
+		{value: wideListColumns, want: strings.Split(wideListColumns, ",")},
+		{value: "id,owner", wantErr: true},
+		{value: "repository", wantErr: true},
+		{value: "", wantErr: true},
+		{value: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			columns, err := parseAgentColumns(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseAgentColumns(%q) = %d columns, want an error", tt.value, len(columns))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAgentColumns(%q): %v", tt.value, err)
+			}
+			var got []string
+			for _, column := range columns {
+				got = append(got, column.name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAgentColumns(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortAgentsKeepsOrderOfEqualKeys(t *testing.T) {
+	created := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	agent := func(id, name, status, repo string, hours int) client.Agent {
+		return client.Agent{
+			ID:        id,
+			Name:      name,
+			Status:    status,
+			Source:    client.Source{Repository: repo},
+			CreatedAt: created.Add(time.Duration(hours) * time.Hour),
+		}
+	}
+	// In API order
+	agents := []client.Agent{
+		agent("bc_1", "beta", client.StatusRunning, "github.com/acme/web", 2),
+		agent("bc_2", "Alpha", client.StatusFailed, "github.com/acme/api", 0),
+		agent("bc_3", "alpha", client.StatusRunning, "github.com/acme/web", 2),
+		agent("bc_4", "beta", client.StatusFailed, "github.com/acme/api", 1),
+		agent("bc_5", "ALPHA", client.StatusRunning, "github.com/acme/web", 0),
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{key: "", want: []string{"bc_1", "bc_2", "bc_3", "bc_4", "bc_5"}},
+		{key: "name", want: []string{"bc_2", "bc_3", "bc_5", "bc_1", "bc_4"}},
+		{key: "-name", want: []string{"bc_1", "bc_4", "bc_2", "bc_3", "bc_5"}},
+		{key: "status", want: []string{"bc_2", "bc_4", "bc_1", "bc_3", "bc_5"}},
+		{key: "-status", want: []string{"bc_1", "bc_3", "bc_5", "bc_2", "bc_4"}},
+		{key: "repo", want: []string{"bc_2", "bc_4", "bc_1", "bc_3", "bc_5"}},
+		{key: "created", want: []string{"bc_2", "bc_5", "bc_4", "bc_1", "bc_3"}},
+		{key: "-Created", want: []string{"bc_1", "bc_3", "bc_4", "bc_2", "bc_5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			sorted := append([]client.Agent(nil), agents...)
+			sortAgents(sorted, tt.key)
+
+			var got []string
+			for _, agent := range sorted {
+				got = append(got, agent.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortAgents(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
+)
+
+// outputOpts holds the output format selected with --output, parsed before
+// any command runs
+var outputOpts output.Options
+
+// parseOutputFlag parses the configured output format into outputOpts
+func parseOutputFlag() error {
+	opts, err := output.Parse(config.GetString("output"))
+	if err != nil {
+		return err
+	}
+	outputOpts = opts
+	return nil
+}
+
+// textOutput returns the writer for human-readable output. What is written
+// to it is not altered, so agent names, summaries and messages appear as
+// the API returned them; see decorated for the CLI's own emoji.
+func textOutput() io.Writer {
+	return os.Stdout
+}
+
+// decorated returns s, one of the CLI's own format strings or labels, as is
+// on a terminal and without its emoji when stdout goes to a file or pipe.
+// Values from the API must not be passed through it.
+func decorated(s string) string {
+	return decoratedFor(os.Stdout, s)
+}
+
+// decoratedFor is like decorated for text written to f
+func decoratedFor(f *os.File, s string) string {
+	if output.IsTerminal(f) {
+		return s
+	}
+	return output.StripEmoji(s)
+}
+
+// printError prints one of the CLI's error messages or hints to stderr, so
+// it never mixes with the output of a command
+func printError(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, decoratedFor(os.Stderr, format), args...)
+}
+
+// messageOutput returns where prompts and progress messages go: stdout with
+// text output, and stderr when stdout carries machine-readable output
+func messageOutput() io.Writer {
+	if outputOpts.IsText() {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+// writeOutput writes v to stdout in the selected machine-readable format
+func writeOutput(v interface{}, table output.Table) {
+	if err := output.Write(os.Stdout, outputOpts, v, table); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(exitError)
+	}
+}
+
+// agentsTable returns the csv form of a list of agents
+func agentsTable(agents []client.Agent) output.Table {
+	table := output.Table{
+		Header: []string{"id", "name", "status", "repository", "ref", "branch", "url", "pr_url", "auto_create_pr", "summary", "created_at"},
+	}
+	for _, agent := range agents {
+		table.Rows = append(table.Rows, []string{
+			agent.ID,
+			agent.Name,
+			agent.Status,
+			agent.Source.Repository,
+			agent.Source.Ref,
+			agent.Target.BranchName,
+			agent.Target.URL,
+			agent.Target.PrURL,
+			fmt.Sprintf("%t", agent.Target.AutoCreatePr),
+			agent.Summary,
+			agent.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return table
+}
+
+// idTable returns the csv form of the responses of commands acting on
+// agents, which only carry the agent IDs
+func idTable(ids ...string) output.Table {
+	table := output.Table{Header: []string{"id"}}
+	for _, id := range ids {
+		table.Rows = append(table.Rows, []string{id})
+	}
+	return table
+}
+
+// agentTable returns the csv form of a single agent
+func agentTable(agent *client.Agent) output.Table {
+	return agentsTable([]client.Agent{*agent})
+}
+
+// conversationTable returns the csv form of a conversation, one row per message
+func conversationTable(conversation *client.ConversationResponse) output.Table {
+	table := output.Table{
+		Header: []string{"id", "type", "text"},
+	}
+	for _, message := range conversation.Messages {
+		table.Rows = append(table.Rows, []string{message.ID, message.Type, message.Text})
+	}
+	return table
+}
+
+// keyInfoTable returns the csv form of API key information
+func keyInfoTable(keyInfo *client.APIKeyInfo) output.Table {
+	return output.Table{
+		Header: []string{"id", "name", "user_email", "created_at"},
+		Rows: [][]string{{
+			keyInfo.ID,
+			keyInfo.Name,
+			keyInfo.UserEmail,
+			keyInfo.CreatedAt.Format(time.RFC3339),
+		}},
+	}
+}
+
+// configTable returns the csv form of config settings
+func configTable(entries []config.Entry) output.Table {
+	table := output.Table{
+		Header: []string{"key", "value", "profile"},
+	}
+	for _, entry := range entries {
+		table.Rows = append(table.Rows, []string{entry.Key, entry.Value, entry.Profile})
+	}
+	return table
+}
+
+// cacheInfoTable returns the csv form of the cache summary
+func cacheInfoTable(info cache.Info) output.Table {
+	var oldest, newest string
+	if !info.Newest.IsZero() {
+		oldest = info.Oldest.Format(time.RFC3339)
+		newest = info.Newest.Format(time.RFC3339)
+	}
+	return output.Table{
+		Header: []string{"dir", "agents", "conversations", "size", "oldest", "newest"},
+		Rows: [][]string{{
+			info.Dir,
+			fmt.Sprint(info.Entries["agents"]),
+			fmt.Sprint(info.Entries["conversations"]),
+			fmt.Sprint(info.Size),
+			oldest,
+			newest,
+		}},
+	}
+}
+
+// doctorTable returns the csv form of a doctor report
+func doctorTable(checks []doctorCheck) output.Table {
+	table := output.Table{
+		Header: []string{"name", "status", "message"},
+	}
+	for _, check := range checks {
+		table.Rows = append(table.Rows, []string{check.Name, check.Status, check.Message})
+	}
+	return table
+}
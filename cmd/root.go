@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var cfgFile string
@@ -22,6 +28,15 @@ This tool allows you to:
 - View agent conversations
 - Add follow-up instructions to agents
 - Manage API keys and configuration`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.ValidateProfileOverride(); err != nil {
+			return err
+		}
+		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+			printConfigReport()
+		}
+		return parseOutputFlag()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Welcome to cursor-cli! Use --help to see available commands.")
 		fmt.Println("Run 'cursor-cli init' to set up your API key.")
@@ -30,9 +45,16 @@ This tool allows you to:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+//
+// Commands run with a context that is cancelled on SIGINT/SIGTERM, so an
+// in-flight API call is aborted when the user presses Ctrl+C.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
+		stop()
 		os.Exit(1)
 	}
 }
@@ -44,34 +66,139 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cursor-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/cursor-cli/config.yaml or $HOME/.cursor-cli.yaml)")
 	rootCmd.PersistentFlags().String("api-key", "", "Cursor API key (can also be set via CURSOR_API_KEY env var)")
+	rootCmd.PersistentFlags().String("profile", "", "Config profile to use (can also be set via CURSOR_PROFILE env var)")
+	rootCmd.PersistentFlags().Duration("request-timeout", client.DefaultTimeout, "Timeout for each API request (e.g. 45s, 2m)")
+	rootCmd.PersistentFlags().String("base-url", "", "Base URL of the Cursor API (default from profile or "+client.DefaultBaseURL+")")
+	rootCmd.PersistentFlags().String("proxy", "", "HTTP, HTTPS or SOCKS5 proxy for API requests (default from HTTPS_PROXY)")
+	rootCmd.PersistentFlags().String("ca-file", "", "PEM file with additional trusted CA certificates")
+	rootCmd.PersistentFlags().Bool("insecure-skip-verify", false, "Skip TLS certificate verification (testing only)")
+	rootCmd.PersistentFlags().StringArray("header", nil, "Extra header sent with every request, as 'Name: value' (repeatable)")
+	rootCmd.PersistentFlags().String("output", "text", "Output format: "+strings.Join(output.Formats, ", "))
+	rootCmd.PersistentFlags().Bool("offline", false, "Read agents and conversations from the local cache instead of the API")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Report the config file and where each setting comes from")
+
+	// Bind the flags to their settings. Each setting can also be set with
+	// an environment variable (see config.EnvName) or in the config file.
+	config.BindFlag("api_key", rootCmd.PersistentFlags().Lookup("api-key"))
+	config.BindFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	config.BindFlag("timeout", rootCmd.PersistentFlags().Lookup("request-timeout"))
+	config.BindFlag("base_url", rootCmd.PersistentFlags().Lookup("base-url"))
+	config.BindFlag("proxy", rootCmd.PersistentFlags().Lookup("proxy"))
+	config.BindFlag("ca_file", rootCmd.PersistentFlags().Lookup("ca-file"))
+	config.BindFlag("insecure_skip_verify", rootCmd.PersistentFlags().Lookup("insecure-skip-verify"))
+	config.BindFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	config.BindFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+
+	_ = rootCmd.RegisterFlagCompletionFunc("output", completeOutputFormats)
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+
+	config.SetDefault("timeout", client.DefaultTimeout)
+	config.SetDefault("base_url", client.DefaultBaseURL)
+	config.SetDefault("output", "text")
+}
+
+// newClient creates an API client for the given key from the configured
+// base URL, transport settings, headers, request timeout, retry policy and
+// local cache.
+// It exits if the settings are invalid.
+func newClient(apiKey string) *client.Client {
+	c, err := client.New(apiKey, clientOptions()...)
+	if err != nil {
+		exitWithError("Error configuring API client", err)
+	}
+	return c
+}
+
+// clientOptions collects the client options from the resolved settings
+func clientOptions() []client.Option {
+	var opts []client.Option
+
+	if baseURL := config.GetString("base_url"); baseURL != "" {
+		opts = append(opts, client.WithBaseURL(baseURL))
+	}
+	if timeout := config.GetDuration("timeout"); timeout > 0 {
+		opts = append(opts, client.WithTimeout(timeout))
+	}
+	if proxy := config.GetString("proxy"); proxy != "" {
+		opts = append(opts, client.WithProxy(proxy))
+	}
+	if caFile := config.GetString("ca_file"); caFile != "" {
+		opts = append(opts, client.WithCAFile(caFile))
+	}
+	if config.GetBool("insecure_skip_verify") {
+		opts = append(opts, client.WithInsecureSkipVerify(true))
+	}
 
-	// Bind the flag to viper
-	viper.BindPFlag("api_key", rootCmd.PersistentFlags().Lookup("api-key"))
+	for name, value := range config.GetStringMapString("headers") {
+		opts = append(opts, client.WithHeader(name, value))
+	}
+	headers, _ := rootCmd.PersistentFlags().GetStringArray("header")
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			exitWithError("Error configuring API client", fmt.Errorf("invalid header %q, expected 'Name: value'", header))
+		}
+		opts = append(opts, client.WithHeader(strings.TrimSpace(name), strings.TrimSpace(value)))
+	}
+
+	// Fetched agents are cached for --offline; without a cache directory
+	// the CLI still works online
+	if store, err := openCache(); err == nil {
+		opts = append(opts, client.WithCache(store))
+		if config.GetBool("offline") {
+			opts = append(opts, client.WithOffline(true))
+		}
+	} else if config.GetBool("offline") {
+		exitWithError("Error configuring API client", err)
+	}
+
+	retry := client.DefaultRetryPolicy()
+	if config.IsSet("retry.max_attempts") {
+		retry.MaxAttempts = config.GetInt("retry.max_attempts")
+	}
+	if config.IsSet("retry.initial_backoff") {
+		retry.InitialBackoff = config.GetDuration("retry.initial_backoff")
+	}
+	if config.IsSet("retry.max_backoff") {
+		retry.MaxBackoff = config.GetDuration("retry.max_backoff")
+	}
+	opts = append(opts, client.WithRetryPolicy(retry))
+
+	return opts
 }
 
-// initConfig reads in config file and ENV variables if set.
+// initConfig reads in the config file
 func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		// Search config in home directory with name ".cursor-cli" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".cursor-cli")
-	}
-
-	viper.AutomaticEnv()         // read in environment variables that match
-	viper.SetEnvPrefix("CURSOR") // will be uppercased automatically
-
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	if err := config.Load(cfgFile); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+
+	// Move settings from before profiles existed into the default profile
+	migrated, err := config.MigrateLegacy()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: could not migrate config file to profiles:", err)
+	} else if migrated {
+		fmt.Fprintf(os.Stderr, "Migrated config file to the %q profile\n", config.DefaultProfile)
+	}
+}
+
+// printConfigReport prints the config file in use and the source of every
+// setting to stderr, for --verbose
+func printConfigReport() {
+	path, _ := config.Path()
+	if _, err := os.Stat(path); err != nil {
+		path += " (not found)"
+	}
+	fmt.Fprintf(os.Stderr, "config file: %s\n", path)
+	fmt.Fprintf(os.Stderr, "profile: %s\n", config.ActiveProfile())
+
+	for _, r := range config.Report() {
+		source := string(r.Source)
+		if r.Origin != "" {
+			source += " " + r.Origin
+		}
+		fmt.Fprintf(os.Stderr, "  %s = %v (%s)\n", r.Key, r.Value, source)
 	}
 }
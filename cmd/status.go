@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -16,53 +15,66 @@ var statusCmd = &cobra.Command{
 	Long: `Get the current status and results of a specific background agent.
 	
 This command shows detailed information about an agent including its status,
-source repository, target branch, summary, and creation time.`,
-	Args: cobra.ExactArgs(1),
+source repository, target branch, summary, and creation time.
+
+` + agentRefHelp + `
+
+Examples:
+  cursor-cli status bc_abc123
+  cursor-cli status @last`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgentRefs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		apiKey, err := config.GetAPIKey()
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
+			printError("❌ Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		agentID := args[0]
-		client := client.NewClient(apiKey)
+		client := newClient(apiKey)
+		defer printCacheNotice(client)
+		agentID := resolveAgentID(cmd.Context(), client, args[0])
 
-		agent, err := client.GetAgentStatus(agentID)
+		agent, err := client.GetAgentStatusContext(cmd.Context(), agentID)
 		if err != nil {
-			fmt.Printf("❌ Error getting agent status: %v\n", err)
-			os.Exit(1)
+			exitWithError("Error getting agent status", err)
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(agent, agentTable(agent))
+			return
 		}
 
 		// Display agent information in a nice format
-		fmt.Printf("🤖 Agent Details\n")
-		fmt.Printf("═══════════════\n\n")
+		out := textOutput()
+		fmt.Fprint(out, decorated("🤖 Agent Details\n"))
+		fmt.Fprintf(out, "═══════════════\n\n")
 
-		fmt.Printf("📋 ID: %s\n", agent.ID)
-		fmt.Printf("📝 Name: %s\n", agent.Name)
-		fmt.Printf("🔄 Status: %s\n", getStatusEmoji(agent.Status))
-		fmt.Printf("📅 Created: %s\n", agent.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(out, decorated("📋 ID: %s\n"), agent.ID)
+		fmt.Fprintf(out, decorated("📝 Name: %s\n"), agent.Name)
+		fmt.Fprintf(out, decorated("🔄 Status: %s\n"), getStatusEmoji(agent.Status))
+		fmt.Fprintf(out, decorated("📅 Created: %s\n"), agent.CreatedAt.Format("2006-01-02 15:04:05"))
 
-		fmt.Printf("\n📂 Source Information\n")
-		fmt.Printf("─────────────────────\n")
-		fmt.Printf("🔗 Repository: %s\n", agent.Source.Repository)
-		fmt.Printf("🌿 Reference: %s\n", agent.Source.Ref)
+		fmt.Fprint(out, decorated("\n📂 Source Information\n"))
+		fmt.Fprintf(out, "─────────────────────\n")
+		fmt.Fprintf(out, decorated("🔗 Repository: %s\n"), agent.Source.Repository)
+		fmt.Fprintf(out, decorated("🌿 Reference: %s\n"), agent.Source.Ref)
 
-		fmt.Printf("\n🎯 Target Information\n")
-		fmt.Printf("─────────────────────\n")
-		fmt.Printf("🌿 Branch: %s\n", agent.Target.BranchName)
-		fmt.Printf("🔗 Agent URL: %s\n", agent.Target.URL)
+		fmt.Fprint(out, decorated("\n🎯 Target Information\n"))
+		fmt.Fprintf(out, "─────────────────────\n")
+		fmt.Fprintf(out, decorated("🌿 Branch: %s\n"), agent.Target.BranchName)
+		fmt.Fprintf(out, decorated("🔗 Agent URL: %s\n"), agent.Target.URL)
 
 		if agent.Target.PrURL != "" {
-			fmt.Printf("🔀 Pull Request: %s\n", agent.Target.PrURL)
+			fmt.Fprintf(out, decorated("🔀 Pull Request: %s\n"), agent.Target.PrURL)
 		}
 
-		fmt.Printf("🔄 Auto Create PR: %t\n", agent.Target.AutoCreatePr)
+		fmt.Fprintf(out, decorated("🔄 Auto Create PR: %t\n"), agent.Target.AutoCreatePr)
 
 		if agent.Summary != "" {
-			fmt.Printf("\n📄 Summary\n")
-			fmt.Printf("──────────\n")
-			fmt.Printf("%s\n", agent.Summary)
+			fmt.Fprint(out, decorated("\n📄 Summary\n"))
+			fmt.Fprintf(out, "──────────\n")
+			fmt.Fprintf(out, "%s\n", agent.Summary)
 		}
 	},
 }
@@ -70,15 +82,17 @@ source repository, target branch, summary, and creation time.`,
 func getStatusEmoji(status string) string {
 	switch status {
 	case "RUNNING":
-		return "🏃 RUNNING"
+		return decorated("🏃 RUNNING")
 	case "COMPLETED":
-		return "✅ COMPLETED"
+		return decorated("✅ COMPLETED")
 	case "FAILED":
-		return "❌ FAILED"
+		return decorated("❌ FAILED")
 	case "CANCELLED":
-		return "🚫 CANCELLED"
+		return decorated("🚫 CANCELLED")
+	case "EXPIRED":
+		return decorated("⏰ EXPIRED")
 	default:
-		return fmt.Sprintf("❓ %s", status)
+		return decorated("❓ ") + status
 	}
 }
 
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// stopCmd represents the stop command
+var stopCmd = &cobra.Command{
+	Use:   "stop <agent-id>",
+	Short: "Stop a running background agent",
+	Long: `Stop a running background agent.
+
+The agent stops working immediately; any changes it has already pushed
+are kept. You will be asked for confirmation unless --yes is given.
+
+` + agentRefHelp + `
+
+Examples:
+  cursor-cli stop bc_abc123
+  cursor-cli stop @last-running --yes`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgentRefs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiKey, err := config.GetAPIKey()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		client := newClient(apiKey)
+		agentID := resolveAgentID(cmd.Context(), client, args[0])
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		if !yes && !confirm(fmt.Sprintf("Stop agent %s?", agentID)) {
+			fmt.Fprintln(messageOutput(), "🚫 Aborted.")
+			return
+		}
+
+		if outputOpts.IsText() {
+			fmt.Printf(decorated("🛑 Stopping agent %s...\n"), agentID)
+		}
+
+		response, err := client.StopAgentContext(cmd.Context(), agentID)
+		if err != nil {
+			exitWithError("Error stopping agent", err)
+		}
+
+		if !outputOpts.IsText() {
+			writeOutput(response, idTable(response.ID))
+			return
+		}
+
+		fmt.Printf(decorated("✅ Agent %s stopped.\n"), agentID)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stopCmd)
+
+	// Add flags
+	stopCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/tui"
 	"github.com/spf13/cobra"
@@ -24,29 +23,48 @@ The TUI provides a rich, interactive experience with:
 - Keyboard shortcuts for efficient navigation
 - Auto-refresh capabilities
 
-This is perfect for monitoring multiple agents and their progress in real-time.`,
+This is perfect for monitoring multiple agents and their progress in real-time.
+
+The dashboard can be narrowed with the same filters as 'cursor-cli list'.
+` + filterHelp + `
+
+Example:
+  cursor-cli tui --status running --repo 'my-org/*'`,
 	Run: func(cmd *cobra.Command, args []string) {
+		agentFilter, err := parseFilterFlags(cmd, false)
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		apiKey, err := config.GetAPIKey()
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
-			fmt.Println("💡 Run 'cursor-cli init' to set up your API key first.")
+			printError("❌ Error: %v\n", err)
+			printError("💡 Run 'cursor-cli init' to set up your API key first.\n")
 			os.Exit(1)
 		}
 
-		client := client.NewClient(apiKey)
+		client := newClient(apiKey)
 
-		fmt.Println("🚀 Starting Cursor Background Agents TUI...")
-		fmt.Println("💡 Press '?' for help, 'q' to quit")
+		fmt.Println(decorated("🚀 Starting Cursor Background Agents TUI..."))
+		fmt.Println(decorated("💡 Press '?' for help, 'q' to quit"))
 
-		if err := tui.Run(client); err != nil {
-			fmt.Printf("❌ Error running TUI: %v\n", err)
+		if err := tui.Run(cmd.Context(), client, tui.Options{
+			RefreshInterval: config.GetDuration("refresh_interval"),
+			Theme:           config.GetString("theme"),
+			Filter:          agentFilter,
+		}); err != nil {
+			printError("❌ Error running TUI: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("👋 Thanks for using Cursor Background Agents CLI!")
+		fmt.Println(decorated("👋 Thanks for using Cursor Background Agents CLI!"))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(tuiCmd)
+
+	// Add flags
+	addFilterFlags(tuiCmd)
 }
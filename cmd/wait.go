@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// waitCmd represents the wait command
+var waitCmd = &cobra.Command{
+	Use:   "wait <agent-id>",
+	Short: "Block until an agent reaches a given status",
+	Long: `Block until a background agent reaches one of the given statuses.
+
+Designed for CI pipelines, the exit code tells what happened:
+  0    the agent reached an awaited status
+  10   the agent failed
+  11   the agent was cancelled
+  12   the agent expired
+  13   the agent finished in another status (e.g. COMPLETED when waiting for FAILED)
+  124  the timeout elapsed first
+
+Failed status checks are reported and retried until the timeout, unless the
+agent does not exist or the API key is rejected.
+
+` + agentRefHelp + `
+
+Examples:
+  cursor-cli wait bc_abc123
+  cursor-cli wait bc_abc123 --for=status=COMPLETED --timeout=30m
+  cursor-cli wait bc_abc123 --for=status=COMPLETED,FAILED`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgentRefs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if config.GetBool("offline") {
+			printError("❌ Error: wait polls the API for changes and cannot run with --offline\n")
+			os.Exit(1)
+		}
+
+		apiKey, err := config.GetAPIKey()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		condition, _ := cmd.Flags().GetString("for")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		statuses, err := parseWaitCondition(condition)
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if interval <= 0 {
+			printError("❌ Error: --interval must be positive\n")
+			os.Exit(1)
+		}
+
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		apiClient := newClient(apiKey)
+		out := textOutput()
+
+		// timedOut reports the timeout, with the last status seen if any
+		timedOut := func(agentID, lastStatus string) {
+			if lastStatus == "" {
+				fmt.Fprintf(out, decorated("⌛ Timed out after %s waiting for agent %s\n"), timeout, agentID)
+			} else {
+				fmt.Fprintf(out, decorated("⌛ Timed out after %s waiting for agent %s (last status: %s)\n"), timeout, agentID, lastStatus)
+			}
+			os.Exit(exitTimeout)
+		}
+
+		agentID, err := apiClient.ResolveAgentRef(ctx, args[0])
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				timedOut(args[0], "")
+			}
+			exitWithError("Error resolving agent", err)
+		}
+
+		fmt.Fprintf(out, decorated("⏳ Waiting for agent %s to reach %s...\n"), agentID, strings.Join(statuses, " or "))
+
+		lastStatus := ""
+		for {
+			agent, err := apiClient.GetAgentStatusContext(ctx, agentID)
+			switch {
+			case err == nil:
+				lastStatus = agent.Status
+			case errors.Is(ctx.Err(), context.DeadlineExceeded):
+				timedOut(agentID, lastStatus)
+			case ctx.Err() != nil || client.IsNotFound(err) || client.IsUnauthorized(err) || client.IsForbidden(err):
+				exitWithError("Error getting agent status", err)
+			default:
+				// Transient failures that outlasted the retries must not
+				// abort a long wait; try again next round
+				fmt.Fprintf(out, decorated("%s ⚠️  %s: %v (will retry)\n"), timestamp(), agentID, err)
+			}
+
+			if err == nil {
+				if containsStatus(statuses, agent.Status) {
+					fmt.Fprintf(out, decorated("✅ Agent %s is %s\n"), agentID, agent.Status)
+					return
+				}
+
+				if client.IsTerminalStatus(agent.Status) {
+					fmt.Fprintf(out, "Agent %s finished as %s\n", agentID, getStatusEmoji(agent.Status))
+					code := exitCodeForStatus(agent.Status)
+					if code == 0 {
+						code = exitConditionNotMet
+					}
+					os.Exit(code)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					timedOut(agentID, lastStatus)
+				}
+				exitWithError("Error waiting for agent", ctx.Err())
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+// waitableStatuses lists the statuses accepted by --for
+var waitableStatuses = []string{
+	client.StatusRunning,
+	client.StatusCompleted,
+	client.StatusFailed,
+	client.StatusCancelled,
+	client.StatusExpired,
+}
+
+// parseWaitCondition parses a --for value of the form status=A[,B...]
+func parseWaitCondition(condition string) ([]string, error) {
+	key, value, ok := strings.Cut(condition, "=")
+	if !ok || strings.TrimSpace(key) != "status" || value == "" {
+		return nil, fmt.Errorf("invalid --for condition %q, expected status=<STATUS>[,<STATUS>...]", condition)
+	}
+
+	var statuses []string
+	for _, status := range strings.Split(value, ",") {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if !containsStatus(waitableStatuses, status) {
+			return nil, fmt.Errorf("unknown status %q in --for, expected one of %s", status, strings.Join(waitableStatuses, ", "))
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// containsStatus reports whether status is one of statuses
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+
+	// Add flags
+	waitCmd.Flags().String("for", "status=COMPLETED", "Condition to wait for, as status=<STATUS>[,<STATUS>...]")
+	waitCmd.Flags().Duration("timeout", 0, "Give up after this long (e.g. 30m); 0 waits forever")
+	waitCmd.Flags().DurationP("interval", "i", 10*time.Second, "How often to poll the agent")
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch <agent-id...>",
+	Short: "Stream status changes of agents until they finish",
+	Long: `Poll one or more background agents and print their status transitions
+(e.g. RUNNING → COMPLETED) and new conversation messages as they appear.
+
+The command returns once every agent has reached a final status. The exit
+code reflects the outcome so it can gate CI steps:
+  0   all agents completed
+  10  an agent failed
+  11  an agent was cancelled
+  12  an agent expired
+
+` + agentRefHelp + `
+
+Examples:
+  cursor-cli watch bc_abc123
+  cursor-cli watch bc_abc123 bc_def456 --interval 30s --messages=false
+  cursor-cli watch @last`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAgentRefs(-1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if config.GetBool("offline") {
+			printError("❌ Error: watch polls the API for changes and cannot run with --offline\n")
+			os.Exit(1)
+		}
+
+		apiKey, err := config.GetAPIKey()
+		if err != nil {
+			printError("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		interval, _ := cmd.Flags().GetDuration("interval")
+		showMessages, _ := cmd.Flags().GetBool("messages")
+		if interval <= 0 {
+			printError("❌ Error: --interval must be positive\n")
+			os.Exit(1)
+		}
+
+		w := &agentWatcher{
+			client:       newClient(apiKey),
+			out:          textOutput(),
+			showMessages: showMessages,
+			agents:       make(map[string]*watchedAgent),
+		}
+		for _, ref := range args {
+			agentID := resolveAgentID(cmd.Context(), w.client, ref)
+			if _, ok := w.agents[agentID]; ok {
+				continue
+			}
+			w.agents[agentID] = &watchedAgent{messages: w.client.TailConversation(agentID)}
+			w.order = append(w.order, agentID)
+		}
+
+		ctx := cmd.Context()
+		for {
+			if err := w.poll(ctx); err != nil {
+				exitWithError("Error watching agents", err)
+			}
+			if w.done() {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				exitWithError("Error watching agents", ctx.Err())
+			case <-time.After(interval):
+			}
+		}
+
+		os.Exit(w.exitCode())
+	},
+}
+
+// watchedAgent tracks what has already been reported for an agent
+type watchedAgent struct {
+	status   string
+	messages *client.ConversationTail
+	primed   bool
+	finished bool
+}
+
+// agentWatcher polls a set of agents and prints what changed
+type agentWatcher struct {
+	client       *client.Client
+	out          io.Writer
+	showMessages bool
+	order        []string
+	agents       map[string]*watchedAgent
+}
+
+// poll refreshes every agent that has not finished yet. Not-found and auth
+// errors are returned; other failures are reported and retried next round.
+func (w *agentWatcher) poll(ctx context.Context) error {
+	for _, agentID := range w.order {
+		state := w.agents[agentID]
+		if state.finished {
+			continue
+		}
+
+		agent, err := w.client.GetAgentStatusContext(ctx, agentID)
+		if err != nil {
+			if ctx.Err() != nil || client.IsNotFound(err) || client.IsUnauthorized(err) || client.IsForbidden(err) {
+				return err
+			}
+			fmt.Fprintf(w.out, decorated("%s ⚠️  %s: %v (will retry)\n"), timestamp(), agentID, err)
+			continue
+		}
+
+		if agent.Status != state.status {
+			if state.status == "" {
+				fmt.Fprintf(w.out, "%s %s: %s\n", timestamp(), agentID, getStatusEmoji(agent.Status))
+			} else {
+				fmt.Fprintf(w.out, "%s %s: %s → %s\n", timestamp(), agentID, state.status, getStatusEmoji(agent.Status))
+			}
+			state.status = agent.Status
+		}
+
+		if w.showMessages {
+			if err := w.printNewMessages(ctx, agentID, state); err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				fmt.Fprintf(w.out, decorated("%s ⚠️  %s: %v (will retry)\n"), timestamp(), agentID, err)
+				continue
+			}
+		}
+
+		if client.IsTerminalStatus(agent.Status) {
+			state.finished = true
+			if agent.Target.PrURL != "" {
+				fmt.Fprintf(w.out, decorated("%s 🔀 %s: pull request %s\n"), timestamp(), agentID, agent.Target.PrURL)
+			}
+		}
+	}
+
+	return nil
+}
+
+// printNewMessages prints conversation messages that have not been printed
+// before. Messages that already existed when watching started are skipped.
+func (w *agentWatcher) printNewMessages(ctx context.Context, agentID string, state *watchedAgent) error {
+	if !state.primed {
+		if err := state.messages.Skip(ctx); err != nil {
+			return err
+		}
+		state.primed = true
+		return nil
+	}
+
+	messages, err := state.messages.Next(ctx)
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		fmt.Fprintf(w.out, decorated("%s "+getMessageTypeEmoji(message.Type)+" %s:\n%s\n"), timestamp(), agentID, message.Text)
+	}
+
+	return nil
+}
+
+// done reports whether every agent has reached a final status
+func (w *agentWatcher) done() bool {
+	for _, state := range w.agents {
+		if !state.finished {
+			return false
+		}
+	}
+	return true
+}
+
+// exitCode returns the exit code for the final statuses, reporting the most
+// severe outcome when agents finished differently
+func (w *agentWatcher) exitCode() int {
+	code := 0
+	for _, state := range w.agents {
+		if c := exitCodeForStatus(state.status); c != 0 && (code == 0 || c < code) {
+			code = c
+		}
+	}
+	return code
+}
+
+// timestamp returns the current time for prefixing watch output
+func timestamp() string {
+	return time.Now().Format("15:04:05")
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	// Add flags
+	watchCmd.Flags().DurationP("interval", "i", 5*time.Second, "How often to poll the agents")
+	watchCmd.Flags().Bool("messages", true, "Print new conversation messages as they appear")
+}
@@ -0,0 +1,244 @@
+// Package cache persists API responses on disk so they can be shown again
+// without network access. Each entry is a JSON file holding the value and
+// the time it was fetched.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Get for keys that were never stored
+var ErrNotFound = errors.New("not in the local cache")
+
+// Store is a directory of cached entries, addressed by slash-separated
+// keys such as "agents/bc_abc123"
+type Store struct {
+	dir string
+}
+
+// entry is the on-disk form of a cached value
+type entry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Info summarizes the contents of a Store
+type Info struct {
+	Dir string `json:"dir"`
+
+	// Entries counts the entries under each top-level key, e.g. "agents"
+	Entries map[string]int `json:"entries"`
+
+	// Size is the total size of the entries in bytes
+	Size int64 `json:"size"`
+
+	// Oldest and Newest are the fetch times of the oldest and newest entry
+	Oldest time.Time `json:"oldest"`
+	Newest time.Time `json:"newest"`
+}
+
+// DefaultDir returns the cursor-cli directory under the user's cache
+// directory, which is $XDG_CACHE_HOME (default ~/.cache) on Linux
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine cache directory: %w", err)
+	}
+	return filepath.Join(dir, "cursor-cli"), nil
+}
+
+// Open returns the store in dir. The directory is created on the first
+// write.
+func Open(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Put stores v under key, fetched now
+func (s *Store) Put(key string, v interface{}) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error encoding cache entry %s: %w", key, err)
+	}
+	data, err = json.Marshal(entry{FetchedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("error encoding cache entry %s: %w", key, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error writing cache entry %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing cache entry %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing cache entry %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get decodes the value stored under key into v and returns when it was
+// fetched. It returns an error wrapping ErrNotFound if key is not stored.
+func (s *Store) Get(key string, v interface{}) (time.Time, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error reading cache entry %s: %w", key, err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return time.Time{}, fmt.Errorf("corrupt cache entry %s: %w", key, err)
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return time.Time{}, fmt.Errorf("corrupt cache entry %s: %w", key, err)
+	}
+	return e.FetchedAt, nil
+}
+
+// Delete removes the entry stored under key, if any
+func (s *Store) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+// Keys returns the keys of the entries directly under prefix, sorted
+func (s *Store) Keys(prefix string) ([]string, error) {
+	rel := filepath.FromSlash(prefix)
+	if prefix == "" || !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("invalid cache key %q", prefix)
+	}
+	dir := filepath.Join(s.dir, rel)
+
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache: %w", err)
+	}
+
+	var keys []string
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		keys = append(keys, prefix+"/"+strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Clear removes every entry of the store. Only stores inside DefaultDir
+// can be cleared, so a bad directory never removes anything else.
+func (s *Store) Clear() error {
+	base, err := DefaultDir()
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(base, s.dir); err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("refusing to clear %s: not inside the cache directory %s", s.dir, base)
+	}
+
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("error clearing cache: %w", err)
+	}
+	return nil
+}
+
+// Info reports how many entries the store holds, their size and age
+func (s *Store) Info() (Info, error) {
+	info := Info{Dir: s.dir, Entries: make(map[string]int)}
+
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == s.dir {
+			return fs.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		group, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		info.Entries[group]++
+
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		info.Size += fileInfo.Size()
+
+		var e entry
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &e) == nil {
+			if info.Oldest.IsZero() || e.FetchedAt.Before(info.Oldest) {
+				info.Oldest = e.FetchedAt
+			}
+			if e.FetchedAt.After(info.Newest) {
+				info.Newest = e.FetchedAt
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return info, fmt.Errorf("error reading cache: %w", err)
+	}
+	return info, nil
+}
+
+// path returns the file an entry is stored in, rejecting keys that would
+// escape the store directory
+func (s *Store) path(key string) (string, error) {
+	rel := filepath.FromSlash(key) + ".json"
+	if key == "" || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid cache key %q", key)
+	}
+	return filepath.Join(s.dir, rel), nil
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClearOnlyInsideDefaultDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	base, err := DefaultDir()
+	if err != nil {
+		t.Fatalf("DefaultDir: %v", err)
+	}
+	victim := t.TempDir()
+	if err := os.WriteFile(filepath.Join(victim, "keep"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{name: "every profile", dir: base},
+		{name: "profile", dir: filepath.Join(base, "work")},
+		{name: "outside", dir: victim, wantErr: true},
+		{name: "escaping", dir: filepath.Join(base, "..", "..", filepath.Base(victim)), wantErr: true},
+		{name: "parent", dir: filepath.Dir(base), wantErr: true},
+		{name: "relative", dir: "cursor-cli", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := Open(tt.dir)
+			if !tt.wantErr {
+				if err := store.Put("agents/bc_abc123", "agent"); err != nil {
+					t.Fatalf("Put: %v", err)
+				}
+			}
+			err := store.Clear()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Clear of %s error = %v, want error %v", tt.dir, err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				if _, err := os.Stat(tt.dir); !os.IsNotExist(err) {
+					t.Errorf("%s still exists after Clear", tt.dir)
+				}
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(victim, "keep")); err != nil {
+		t.Errorf("Clear removed a file outside the cache: %v", err)
+	}
+}
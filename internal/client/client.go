@@ -2,15 +2,23 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
 )
 
 const (
-	BaseURL = "https://api.cursor.com/v0"
+	// DefaultBaseURL is the base URL of the Cursor API
+	DefaultBaseURL = "https://api.cursor.com/v0"
+
+	// DefaultTimeout is the HTTP timeout used when none is configured
+	DefaultTimeout = 30 * time.Second
 )
 
 // Client represents the Cursor API client
@@ -18,14 +26,63 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	APIKey     string
+	Retry      RetryPolicy
+
+	// Headers are sent with every request
+	Headers http.Header
+
+	// agents caches the agent list used by ResolveAgentRef; agentsCached
+	// tells whether it was read from the cache rather than the API
+	agentsMu     sync.Mutex
+	agents       []Agent
+	agentsCached bool
+
+	// cache persists fetched agents and conversations; in offline mode
+	// they are read from it instead of the API
+	cache    *cache.Store
+	offline  bool
+	cachedMu sync.Mutex
+	cachedAt time.Time
+
+	// responses keeps ETag-tagged responses for conditional requests
+	responses responseCache
 }
 
-// NewClient creates a new Cursor API client
+// NewClient creates a new Cursor API client with the default settings. Use
+// New to configure the base URL, transport or headers.
 func NewClient(apiKey string) *Client {
-	return &Client{
-		BaseURL:    BaseURL,
-		HTTPClient: &http.Client{Timeout: 30 * time.Second},
-		APIKey:     apiKey,
+	c, _ := New(apiKey)
+	return c
+}
+
+// Agent statuses reported by the API
+const (
+	StatusCreating  = "CREATING"
+	StatusRunning   = "RUNNING"
+	StatusCompleted = "COMPLETED"
+	StatusFailed    = "FAILED"
+	StatusCancelled = "CANCELLED"
+	StatusExpired   = "EXPIRED"
+)
+
+// Statuses lists every agent status, in lifecycle order
+var Statuses = []string{
+	StatusCreating,
+	StatusRunning,
+	StatusCompleted,
+	StatusFailed,
+	StatusCancelled,
+	StatusExpired,
+}
+
+// IsTerminalStatus reports whether an agent with the given status has
+// stopped working and will not change status again
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case StatusCompleted, StatusFailed, StatusCancelled, StatusExpired:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -49,8 +106,8 @@ type Source struct {
 // Target represents the target branch and PR information
 type Target struct {
 	BranchName   string `json:"branchName"`
-	URL          string `json:"url"`
-	PrURL        string `json:"prUrl"`
+	URL          string `json:"url,omitempty"`
+	PrURL        string `json:"prUrl,omitempty"`
 	AutoCreatePr bool   `json:"autoCreatePr"`
 }
 
@@ -104,42 +161,138 @@ type Dimension struct {
 	Height int `json:"height"`
 }
 
+// LaunchAgentRequest represents a request to launch a new background agent
+type LaunchAgentRequest struct {
+	Prompt Prompt  `json:"prompt"`
+	Source Source  `json:"source"`
+	Target *Target `json:"target,omitempty"`
+	Model  string  `json:"model,omitempty"`
+}
+
 // FollowupResponse represents the response from adding a follow-up
 type FollowupResponse struct {
 	ID string `json:"id"`
 }
 
-// makeRequest makes an HTTP request to the API
-func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
+// StopAgentResponse represents the response from stopping an agent
+type StopAgentResponse struct {
+	ID string `json:"id"`
+}
+
+// DeleteAgentResponse represents the response from deleting an agent
+type DeleteAgentResponse struct {
+	ID string `json:"id"`
+}
+
+// makeRequest makes an HTTP request to the API. The request is aborted as
+// soon as ctx is cancelled or its deadline expires. Transient failures are
+// retried according to the client's RetryPolicy. GET requests are sent with
+// If-None-Match when an earlier response carried an ETag, and an unchanged
+// resource is answered from memory.
+func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+	if c.offline {
+		return nil, fmt.Errorf("%s %s: %w", method, endpoint, ErrOffline)
+	}
+
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, c.BaseURL+endpoint, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+	key := idempotencyKey(ctx)
+	canRetry := isIdempotent(method) || key != ""
+
+	for attempt := 1; ; attempt++ {
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+endpoint, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		for key, values := range c.Headers {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		c.setConditional(req, endpoint)
+
+		retry := canRetry && attempt < c.Retry.MaxAttempts
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			if !retry || ctx.Err() != nil {
+				return nil, fmt.Errorf("error making request: %w", err)
+			}
+			if err := sleep(ctx, c.Retry.backoff(attempt)); err != nil {
+				return nil, fmt.Errorf("error making request: %w", err)
+			}
+			continue
+		}
+
+		if !retry || !isRetryableStatus(resp.StatusCode) {
+			return c.revalidate(endpoint, resp)
+		}
+
+		delay := c.Retry.backoff(attempt)
+		if after, ok := retryAfter(resp); ok {
+			if c.Retry.MaxBackoff > 0 && after > c.Retry.MaxBackoff {
+				// The server wants us to back off longer than we are
+				// willing to wait; surface the response as is
+				return resp, nil
+			}
+			delay = after
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("error making request: %w", err)
+		}
 	}
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+// ListAgents retrieves a paginated list of all background agents
+func (c *Client) ListAgents(limit int, cursor string) (*ListAgentsResponse, error) {
+	return c.ListAgentsContext(context.Background(), limit, cursor)
+}
+
+// ListAgentsContext is like ListAgents but honors cancellation and deadlines of ctx
+func (c *Client) ListAgentsContext(ctx context.Context, limit int, cursor string) (*ListAgentsResponse, error) {
+	if c.offline {
+		return c.cachedAgentList(limit, cursor)
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	result, err := c.fetchAgentList(ctx, limit, cursor)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, err
+	}
+
+	for _, agent := range result.Agents {
+		c.storeAgent(agent)
 	}
 
-	return resp, nil
+	return result, nil
 }
 
-// ListAgents retrieves a paginated list of all background agents
-func (c *Client) ListAgents(limit int, cursor string) (*ListAgentsResponse, error) {
+// fetchAgentList requests a page of agents from the API without saving
+// them in the cache
+func (c *Client) fetchAgentList(ctx context.Context, limit int, cursor string) (*ListAgentsResponse, error) {
 	endpoint := "/agents"
 
 	// Add query parameters
@@ -156,15 +309,14 @@ func (c *Client) ListAgents(limit int, cursor string) (*ListAgentsResponse, erro
 		}
 	}
 
-	resp, err := c.makeRequest("GET", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	var result ListAgentsResponse
@@ -175,19 +327,57 @@ func (c *Client) ListAgents(limit int, cursor string) (*ListAgentsResponse, erro
 	return &result, nil
 }
 
+// LaunchAgent starts a new background agent on the given repository
+func (c *Client) LaunchAgent(request LaunchAgentRequest) (*Agent, error) {
+	return c.LaunchAgentContext(context.Background(), request)
+}
+
+// LaunchAgentContext is like LaunchAgent but honors cancellation and deadlines of ctx
+func (c *Client) LaunchAgentContext(ctx context.Context, request LaunchAgentRequest) (*Agent, error) {
+	endpoint := "/agents"
+
+	resp, err := c.makeRequest(ctx, "POST", endpoint, request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+
+	var result Agent
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	// @last must see the new agent
+	c.forgetResolveAgents()
+
+	return &result, nil
+}
+
 // GetAgentStatus gets the current status and results of a specific background agent
 func (c *Client) GetAgentStatus(agentID string) (*Agent, error) {
+	return c.GetAgentStatusContext(context.Background(), agentID)
+}
+
+// GetAgentStatusContext is like GetAgentStatus but honors cancellation and deadlines of ctx
+func (c *Client) GetAgentStatusContext(ctx context.Context, agentID string) (*Agent, error) {
+	if c.offline {
+		return c.cachedAgent(agentID)
+	}
+
 	endpoint := fmt.Sprintf("/agents/%s", agentID)
 
-	resp, err := c.makeRequest("GET", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	var result Agent
@@ -195,22 +385,32 @@ func (c *Client) GetAgentStatus(agentID string) (*Agent, error) {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
+	c.storeAgent(result)
+
 	return &result, nil
 }
 
 // GetAgentConversation retrieves the conversation history of a background agent
 func (c *Client) GetAgentConversation(agentID string) (*ConversationResponse, error) {
+	return c.GetAgentConversationContext(context.Background(), agentID)
+}
+
+// GetAgentConversationContext is like GetAgentConversation but honors cancellation and deadlines of ctx
+func (c *Client) GetAgentConversationContext(ctx context.Context, agentID string) (*ConversationResponse, error) {
+	if c.offline {
+		return c.cachedConversation(agentID)
+	}
+
 	endpoint := fmt.Sprintf("/agents/%s/conversation", agentID)
 
-	resp, err := c.makeRequest("GET", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	var result ConversationResponse
@@ -218,28 +418,38 @@ func (c *Client) GetAgentConversation(agentID string) (*ConversationResponse, er
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
+	c.storeConversation(agentID, result)
+
 	return &result, nil
 }
 
 // AddFollowup sends an additional instruction to a running background agent
 func (c *Client) AddFollowup(agentID string, prompt string) (*FollowupResponse, error) {
+	return c.AddFollowupContext(context.Background(), agentID, prompt)
+}
+
+// AddFollowupContext is like AddFollowup but honors cancellation and deadlines of ctx
+func (c *Client) AddFollowupContext(ctx context.Context, agentID string, prompt string) (*FollowupResponse, error) {
+	return c.AddFollowupPromptContext(ctx, agentID, Prompt{Text: prompt})
+}
+
+// AddFollowupPromptContext sends a follow-up prompt, which may include
+// images, to a running background agent
+func (c *Client) AddFollowupPromptContext(ctx context.Context, agentID string, prompt Prompt) (*FollowupResponse, error) {
 	endpoint := fmt.Sprintf("/agents/%s/followup", agentID)
 
 	request := FollowupRequest{
-		Prompt: Prompt{
-			Text: prompt,
-		},
+		Prompt: prompt,
 	}
 
-	resp, err := c.makeRequest("POST", endpoint, request)
+	resp, err := c.makeRequest(ctx, "POST", endpoint, request)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	var result FollowupResponse
@@ -250,19 +460,87 @@ func (c *Client) AddFollowup(agentID string, prompt string) (*FollowupResponse,
 	return &result, nil
 }
 
+// StopAgent stops a running background agent
+func (c *Client) StopAgent(agentID string) (*StopAgentResponse, error) {
+	return c.StopAgentContext(context.Background(), agentID)
+}
+
+// StopAgentContext is like StopAgent but honors cancellation and deadlines of ctx
+func (c *Client) StopAgentContext(ctx context.Context, agentID string) (*StopAgentResponse, error) {
+	endpoint := fmt.Sprintf("/agents/%s/stop", agentID)
+
+	resp, err := c.makeRequest(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result StopAgentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	// The agent is no longer running
+	c.forgetResolveAgents()
+
+	return &result, nil
+}
+
+// DeleteAgent permanently deletes a background agent
+func (c *Client) DeleteAgent(agentID string) (*DeleteAgentResponse, error) {
+	return c.DeleteAgentContext(context.Background(), agentID)
+}
+
+// DeleteAgentContext is like DeleteAgent but honors cancellation and deadlines of ctx
+func (c *Client) DeleteAgentContext(ctx context.Context, agentID string) (*DeleteAgentResponse, error) {
+	endpoint := fmt.Sprintf("/agents/%s", agentID)
+
+	resp, err := c.makeRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		c.forgetAgent(agentID)
+		return &DeleteAgentResponse{ID: agentID}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result DeleteAgentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	c.forgetAgent(agentID)
+
+	return &result, nil
+}
+
 // GetAPIKeyInfo retrieves information about the current API key
 func (c *Client) GetAPIKeyInfo() (*APIKeyInfo, error) {
+	return c.GetAPIKeyInfoContext(context.Background())
+}
+
+// GetAPIKeyInfoContext is like GetAPIKeyInfo but honors cancellation and deadlines of ctx
+func (c *Client) GetAPIKeyInfoContext(ctx context.Context) (*APIKeyInfo, error) {
 	endpoint := "/me"
 
-	resp, err := c.makeRequest("GET", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp)
 	}
 
 	var result APIKeyInfo
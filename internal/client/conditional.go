@@ -0,0 +1,123 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxCachedResponses bounds the number of responses kept for conditional
+// requests; when full, an arbitrary entry is evicted
+const maxCachedResponses = 256
+
+// cachedResponse is a response body kept with the ETag it was served with
+type cachedResponse struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// responseCache keeps the last response of GET endpoints that carried an
+// ETag, so they can be revalidated with If-None-Match instead of being
+// downloaded again. It is safe for concurrent use.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+// get returns the cached response of endpoint
+func (rc *responseCache) get(endpoint string) (cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[endpoint]
+	return entry, ok
+}
+
+// put caches the response of endpoint
+func (rc *responseCache) put(endpoint string, entry cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.entries == nil {
+		rc.entries = make(map[string]cachedResponse)
+	}
+	if _, ok := rc.entries[endpoint]; !ok && len(rc.entries) >= maxCachedResponses {
+		for key := range rc.entries {
+			delete(rc.entries, key)
+			break
+		}
+	}
+	rc.entries[endpoint] = entry
+}
+
+// forget drops the cached response of endpoint
+func (rc *responseCache) forget(endpoint string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.entries, endpoint)
+}
+
+// setConditional adds If-None-Match to a GET request whose endpoint has a
+// cached response
+func (c *Client) setConditional(req *http.Request, endpoint string) {
+	if req.Method != http.MethodGet {
+		return
+	}
+	if entry, ok := c.responses.get(endpoint); ok {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+}
+
+// revalidate completes a conditional request. A 304 Not Modified is turned
+// into a 200 OK carrying the cached body, so callers decode it as usual; a
+// 200 OK with an ETag is cached for the next request.
+func (c *Client) revalidate(endpoint string, resp *http.Response) (*http.Response, error) {
+	if resp.Request == nil || resp.Request.Method != http.MethodGet {
+		return resp, nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		entry, ok := c.responses.get(endpoint)
+		if !ok {
+			// Not something we asked for; let the caller report it
+			return resp, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		header := entry.header.Clone()
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			header.Set("ETag", etag)
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(entry.body)),
+			ContentLength: int64(len(entry.body)),
+			Request:       resp.Request,
+		}, nil
+
+	case http.StatusOK:
+		etag := resp.Header.Get("ETag")
+		if etag == "" {
+			c.responses.forget(endpoint)
+			return resp, nil
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading response: %w", err)
+		}
+		c.responses.put(endpoint, cachedResponse{etag: etag, header: resp.Header.Clone(), body: body})
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+
+	return resp, nil
+}
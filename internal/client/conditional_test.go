@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// conversationServer is a fake API serving one conversation with an ETag
+// and answering 304 Not Modified to requests that already have it
+type conversationServer struct {
+	mu          sync.Mutex
+	id          string
+	messages    []Message
+	etag        string
+	full        int // 200 responses sent
+	notModified int // 304 responses sent
+	ifNoneMatch string
+}
+
+// set replaces the conversation and its ETag
+func (s *conversationServer) set(id, etag string, messages ...Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id, s.etag, s.messages = id, etag, messages
+}
+
+func (s *conversationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ifNoneMatch = r.Header.Get("If-None-Match")
+	if s.etag != "" && s.ifNoneMatch == s.etag {
+		s.notModified++
+		w.Header().Set("ETag", s.etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var items []string
+	for _, m := range s.messages {
+		items = append(items, fmt.Sprintf(`{"id":%q,"type":%q,"text":%q}`, m.ID, m.Type, m.Text))
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	s.full++
+	fmt.Fprintf(w, `{"id":%q,"messages":[%s]}`, s.id, strings.Join(items, ","))
+}
+
+// counts returns the number of full and not modified responses sent
+func (s *conversationServer) counts() (full, notModified int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.full, s.notModified
+}
+
+// texts returns the texts of messages
+func texts(messages []Message) []string {
+	var texts []string
+	for _, m := range messages {
+		texts = append(texts, m.Text)
+	}
+	return texts
+}
+
+func TestNotModifiedReturnsCachedBody(t *testing.T) {
+	srv := &conversationServer{}
+	srv.set("conv_1", `"v1"`, Message{ID: "m1", Type: "user_message", Text: "hello"})
+	c := newTestClient(t, RetryPolicy{MaxAttempts: 1}, srv.ServeHTTP)
+
+	first, err := c.GetAgentConversationContext(context.Background(), "bc_abc123")
+	if err != nil {
+		t.Fatalf("GetAgentConversation: %v", err)
+	}
+	second, err := c.GetAgentConversationContext(context.Background(), "bc_abc123")
+	if err != nil {
+		t.Fatalf("GetAgentConversation after 304: %v", err)
+	}
+
+	if srv.ifNoneMatch != `"v1"` {
+		t.Errorf("second request sent If-None-Match %q, want %q", srv.ifNoneMatch, `"v1"`)
+	}
+	if full, notModified := srv.counts(); full != 1 || notModified != 1 {
+		t.Errorf("server sent %d full and %d not modified responses, want 1 and 1", full, notModified)
+	}
+	if !reflect.DeepEqual(second, first) {
+		t.Errorf("conversation after 304 = %+v, want the cached %+v", second, first)
+	}
+}
+
+func TestChangedETagReplacesCachedBody(t *testing.T) {
+	srv := &conversationServer{}
+	srv.set("conv_1", `"v1"`, Message{ID: "m1", Text: "hello"})
+	c := newTestClient(t, RetryPolicy{MaxAttempts: 1}, srv.ServeHTTP)
+	ctx := context.Background()
+
+	if _, err := c.GetAgentConversationContext(ctx, "bc_abc123"); err != nil {
+		t.Fatalf("GetAgentConversation: %v", err)
+	}
+
+	srv.set("conv_1", `"v2"`, Message{ID: "m1", Text: "hello"}, Message{ID: "m2", Text: "done"})
+	changed, err := c.GetAgentConversationContext(ctx, "bc_abc123")
+	if err != nil {
+		t.Fatalf("GetAgentConversation: %v", err)
+	}
+	if got, want := texts(changed.Messages), []string{"hello", "done"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changed conversation = %v, want %v", got, want)
+	}
+
+	// Revalidated against the new ETag, answered from the new body
+	again, err := c.GetAgentConversationContext(ctx, "bc_abc123")
+	if err != nil {
+		t.Fatalf("GetAgentConversation: %v", err)
+	}
+	if srv.ifNoneMatch != `"v2"` {
+		t.Errorf("request sent If-None-Match %q, want %q", srv.ifNoneMatch, `"v2"`)
+	}
+	if got, want := texts(again.Messages), []string{"hello", "done"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("conversation after 304 = %v, want %v", got, want)
+	}
+	if full, notModified := srv.counts(); full != 2 || notModified != 1 {
+		t.Errorf("server sent %d full and %d not modified responses, want 2 and 1", full, notModified)
+	}
+}
+
+func TestResponseWithoutETagIsNotRevalidated(t *testing.T) {
+	srv := &conversationServer{}
+	srv.set("conv_1", `"v1"`, Message{ID: "m1", Text: "hello"})
+	c := newTestClient(t, RetryPolicy{MaxAttempts: 1}, srv.ServeHTTP)
+	ctx := context.Background()
+
+	if _, err := c.GetAgentConversationContext(ctx, "bc_abc123"); err != nil {
+		t.Fatalf("GetAgentConversation: %v", err)
+	}
+	srv.set("conv_1", "", Message{ID: "m1", Text: "hello"})
+	if _, err := c.GetAgentConversationContext(ctx, "bc_abc123"); err != nil {
+		t.Fatalf("GetAgentConversation: %v", err)
+	}
+	if _, err := c.GetAgentConversationContext(ctx, "bc_abc123"); err != nil {
+		t.Fatalf("GetAgentConversation: %v", err)
+	}
+
+	if srv.ifNoneMatch != "" {
+		t.Errorf("request sent If-None-Match %q after a response without ETag, want none", srv.ifNoneMatch)
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrOffline is returned in offline mode for requests that need the API
+var ErrOffline = errors.New("not available offline")
+
+// APIError is returned by client methods when the API responds with an
+// unexpected status code. Use errors.As or the Is* helpers to inspect it.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API request failed with status %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, " (%s)", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request ID: %s]", e.RequestID)
+	}
+	return b.String()
+}
+
+// newAPIError builds an APIError from a failed response, reading the JSON
+// error body when there is one and falling back to the raw body otherwise
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	code, message := parseErrorBody(body)
+	apiErr.Code = code
+	apiErr.Message = message
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
+
+// parseErrorBody extracts the error code and message from the error body
+// shapes the API uses: {"error": "..."}, {"error": {"code", "message"}}
+// and {"code", "message"}
+func parseErrorBody(body []byte) (code, message string) {
+	var payload struct {
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", ""
+	}
+
+	code, message = payload.Code, payload.Message
+	if len(payload.Error) == 0 {
+		return code, message
+	}
+
+	var text string
+	if err := json.Unmarshal(payload.Error, &text); err == nil {
+		if message == "" {
+			message = text
+		}
+		return code, message
+	}
+
+	var nested struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(payload.Error, &nested); err == nil {
+		if nested.Code != "" {
+			code = nested.Code
+		}
+		if nested.Message != "" {
+			message = nested.Message
+		}
+	}
+
+	return code, message
+}
+
+// statusCode returns the HTTP status of err if it is an APIError, or 0
+func statusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsUnauthorized reports whether err is a 401 response, meaning the API key
+// is missing, invalid or revoked
+func IsUnauthorized(err error) bool {
+	return statusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether err is a 403 response
+func IsForbidden(err error) bool {
+	return statusCode(err) == http.StatusForbidden
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsRateLimited reports whether err is a 429 response
+func IsRateLimited(err error) bool {
+	return statusCode(err) == http.StatusTooManyRequests
+}
+
+// IsServerError reports whether err is a 5xx response
+func IsServerError(err error) bool {
+	return statusCode(err) >= 500
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"os"
+
+	// Register the formats accepted by the API with image.DecodeConfig
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	// MaxImages is the largest number of images attached to one prompt
+	MaxImages = 5
+
+	// MaxImageSize is the largest accepted image file, in bytes
+	MaxImageSize = 10 << 20
+)
+
+// LoadImage reads a PNG, JPEG or GIF file and returns it base64-encoded
+// together with its dimensions, ready to attach to a Prompt
+func LoadImage(path string) (Image, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Image{}, err
+	}
+	if info.IsDir() {
+		return Image{}, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > MaxImageSize {
+		return Image{}, fmt.Errorf("%s is %.1f MiB, images must be at most %d MiB", path, float64(info.Size())/(1<<20), MaxImageSize>>20)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Image{}, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("%s is not a PNG, JPEG or GIF image: %w", path, err)
+	}
+
+	return Image{
+		Data: base64.StdEncoding.EncodeToString(data),
+		Dimension: Dimension{
+			Width:  config.Width,
+			Height: config.Height,
+		},
+	}, nil
+}
+
+// LoadImages loads every image in paths, enforcing MaxImages
+func LoadImages(paths []string) ([]Image, error) {
+	if len(paths) > MaxImages {
+		return nil, fmt.Errorf("too many images: %d given, at most %d allowed", len(paths), MaxImages)
+	}
+
+	images := make([]Image, 0, len(paths))
+	for _, path := range paths {
+		img, err := LoadImage(path)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+
+	return images, nil
+}
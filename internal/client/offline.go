@@ -0,0 +1,145 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
+)
+
+// Offline reports whether the client serves agents and conversations from
+// its cache instead of the API
+func (c *Client) Offline() bool {
+	return c.offline
+}
+
+// CachedAt returns when the oldest data the client served from its cache
+// was fetched from the API, or the zero time if nothing was served from
+// the cache
+func (c *Client) CachedAt() time.Time {
+	c.cachedMu.Lock()
+	defer c.cachedMu.Unlock()
+	return c.cachedAt
+}
+
+// noteCached records that data fetched at fetchedAt was served from the
+// cache
+func (c *Client) noteCached(fetchedAt time.Time) {
+	c.cachedMu.Lock()
+	defer c.cachedMu.Unlock()
+	if c.cachedAt.IsZero() || fetchedAt.Before(c.cachedAt) {
+		c.cachedAt = fetchedAt
+	}
+}
+
+// agentKey and conversationKey return the cache keys of an agent and its
+// conversation
+func agentKey(agentID string) string {
+	return "agents/" + url.PathEscape(agentID)
+}
+
+func conversationKey(agentID string) string {
+	return "conversations/" + url.PathEscape(agentID)
+}
+
+// storeAgent saves agent in the cache, if there is one. The cache is best
+// effort, so errors are ignored.
+func (c *Client) storeAgent(agent Agent) {
+	if c.cache != nil && agent.ID != "" {
+		_ = c.cache.Put(agentKey(agent.ID), agent)
+	}
+}
+
+// storeConversation saves the conversation of an agent in the cache
+func (c *Client) storeConversation(agentID string, conversation ConversationResponse) {
+	if c.cache != nil {
+		_ = c.cache.Put(conversationKey(agentID), conversation)
+	}
+}
+
+// forgetAgent removes a deleted agent and its conversation from the cache
+func (c *Client) forgetAgent(agentID string) {
+	if c.cache != nil {
+		_ = c.cache.Delete(agentKey(agentID))
+		_ = c.cache.Delete(conversationKey(agentID))
+	}
+	c.forgetResolveAgents()
+}
+
+// cachedAgent returns an agent from the cache
+func (c *Client) cachedAgent(agentID string) (*Agent, error) {
+	var agent Agent
+	fetchedAt, err := c.cache.Get(agentKey(agentID), &agent)
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("agent %s is %w", agentID, cache.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.noteCached(fetchedAt)
+	return &agent, nil
+}
+
+// cachedConversation returns the conversation of an agent from the cache
+func (c *Client) cachedConversation(agentID string) (*ConversationResponse, error) {
+	var conversation ConversationResponse
+	fetchedAt, err := c.cache.Get(conversationKey(agentID), &conversation)
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("conversation of agent %s is %w", agentID, cache.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.noteCached(fetchedAt)
+	return &conversation, nil
+}
+
+// cachedAgentList pages through the cached agents, newest first. The
+// cursor is the offset of the page.
+func (c *Client) cachedAgentList(limit int, cursor string) (*ListAgentsResponse, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid cursor %q in offline mode", cursor)
+		}
+	}
+
+	keys, err := c.cache.Keys("agents")
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		agent     Agent
+		fetchedAt time.Time
+	}
+	var agents []entry
+	for _, key := range keys {
+		var agent Agent
+		fetchedAt, err := c.cache.Get(key, &agent)
+		if err != nil {
+			continue
+		}
+		agents = append(agents, entry{agent, fetchedAt})
+	}
+	sort.SliceStable(agents, func(i, j int) bool {
+		return agents[i].agent.CreatedAt.After(agents[j].agent.CreatedAt)
+	})
+
+	result := &ListAgentsResponse{Agents: []Agent{}}
+	start, end := min(offset, len(agents)), len(agents)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+		result.NextCursor = strconv.Itoa(end)
+	}
+	for _, cached := range agents[start:end] {
+		result.Agents = append(result.Agents, cached.agent)
+		c.noteCached(cached.fetchedAt)
+	}
+	return result, nil
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
+)
+
+// Option configures a Client created with New
+type Option func(*options) error
+
+// options collects the settings the Client and its transport are built from
+type options struct {
+	baseURL    string
+	timeout    time.Duration
+	proxy      *url.URL
+	caFile     string
+	insecure   bool
+	headers    http.Header
+	retry      RetryPolicy
+	httpClient *http.Client
+	cache      *cache.Store
+	offline    bool
+}
+
+// WithBaseURL sets the base URL of the API, e.g. a local mock endpoint
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) error {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q", baseURL)
+		}
+		o.baseURL = strings.TrimRight(baseURL, "/")
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid timeout %s", timeout)
+		}
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// WithProxy routes requests through the given HTTP, HTTPS or SOCKS5 proxy.
+// Without it the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+// variables are honored.
+func WithProxy(proxyURL string) Option {
+	return func(o *options) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q", proxyURL)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("unsupported proxy scheme %q (supported: http, https, socks5)", u.Scheme)
+		}
+		o.proxy = u
+		return nil
+	}
+}
+
+// WithCAFile trusts the PEM encoded certificates in path in addition to
+// the system roots
+func WithCAFile(path string) Option {
+	return func(o *options) error {
+		o.caFile = path
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server's TLS
+// certificate. Only use this against test endpoints.
+func WithInsecureSkipVerify(insecure bool) Option {
+	return func(o *options) error {
+		o.insecure = insecure
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key, value string) Option {
+	return func(o *options) error {
+		if key == "" {
+			return fmt.Errorf("header name cannot be empty")
+		}
+		o.headers.Add(key, value)
+		return nil
+	}
+}
+
+// WithRetryPolicy sets how transient failures are retried
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) error {
+		o.retry = policy
+		return nil
+	}
+}
+
+// WithHTTPClient uses the given HTTP client as is. The timeout, proxy and
+// TLS options are ignored.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) error {
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithCache saves the agents and conversations the client fetches in
+// store, so they can be read back in offline mode
+func WithCache(store *cache.Store) Option {
+	return func(o *options) error {
+		o.cache = store
+		return nil
+	}
+}
+
+// WithOffline serves agents and conversations from the cache instead of
+// the API. Other requests fail with ErrOffline. Requires WithCache.
+func WithOffline(offline bool) Option {
+	return func(o *options) error {
+		o.offline = offline
+		return nil
+	}
+}
+
+// New creates a Cursor API client configured by opts
+func New(apiKey string, opts ...Option) (*Client, error) {
+	o := options{
+		baseURL: DefaultBaseURL,
+		timeout: DefaultTimeout,
+		headers: make(http.Header),
+		retry:   DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.offline && o.cache == nil {
+		return nil, fmt.Errorf("offline mode needs a cache")
+	}
+
+	httpClient := o.httpClient
+	if httpClient == nil {
+		transport, err := o.transport()
+		if err != nil {
+			return nil, err
+		}
+		httpClient = &http.Client{Timeout: o.timeout, Transport: transport}
+	}
+
+	return &Client{
+		BaseURL:    o.baseURL,
+		HTTPClient: httpClient,
+		APIKey:     apiKey,
+		Headers:    o.headers,
+		Retry:      o.retry,
+		cache:      o.cache,
+		offline:    o.offline,
+	}, nil
+}
+
+// transport builds the HTTP transport from the proxy and TLS options
+func (o options) transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if o.proxy != nil {
+		transport.Proxy = http.ProxyURL(o.proxy)
+	}
+
+	if o.caFile == "" && !o.insecure {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.insecure,
+	}
+	if o.caFile != "" {
+		pem, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", o.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import "context"
+
+// MaxPageSize is the largest number of agents the API returns per page
+const MaxPageSize = 100
+
+// Pager walks through the agent list page by page, following NextCursor
+type Pager struct {
+	client   *Client
+	pageSize int
+	cursor   string
+	done     bool
+}
+
+// NewPager returns a Pager fetching pageSize agents per request, starting at
+// cursor (empty for the first page)
+func (c *Client) NewPager(pageSize int, cursor string) *Pager {
+	return &Pager{
+		client:   c,
+		pageSize: pageSize,
+		cursor:   cursor,
+	}
+}
+
+// Next fetches the next page of agents. Once the last page has been
+// returned, Done reports true and Next returns no agents.
+func (p *Pager) Next(ctx context.Context) ([]Agent, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	response, err := p.client.ListAgentsContext(ctx, p.pageSize, p.cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	p.cursor = response.NextCursor
+	p.done = response.NextCursor == ""
+
+	return response.Agents, nil
+}
+
+// SetPageSize changes how many agents the following requests fetch
+func (p *Pager) SetPageSize(pageSize int) {
+	p.pageSize = pageSize
+}
+
+// Done reports whether the last page has been fetched
+func (p *Pager) Done() bool {
+	return p.done
+}
+
+// Cursor returns the cursor of the next page, or "" after the last page
+func (p *Pager) Cursor() string {
+	return p.cursor
+}
+
+// AllAgents retrieves agents across all pages. When max is positive it stops
+// once at least max agents have been fetched and returns the first max.
+func (c *Client) AllAgents(ctx context.Context, max int) ([]Agent, error) {
+	pager := c.NewPager(MaxPageSize, "")
+
+	var agents []Agent
+	for !pager.Done() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		agents = append(agents, page...)
+
+		if max > 0 && len(agents) >= max {
+			return agents[:max], nil
+		}
+	}
+
+	return agents, nil
+}
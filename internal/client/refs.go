@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
+)
+
+// Special agent references accepted by ResolveAgentRef
+const (
+	RefLast        = "@last"         // Most recently created agent
+	RefLastRunning = "@last-running" // Most recently created running agent
+)
+
+// maxResolveAgents bounds how many agents are listed to resolve a reference
+const maxResolveAgents = 1000
+
+// resolveCacheKey is the cache entry holding the agent list of KnownAgents,
+// and resolveCacheTTL how long it is reused
+const (
+	resolveCacheKey = "known-agents"
+	resolveCacheTTL = 30 * time.Second
+)
+
+// resolveCache is the agent list as stored in the cache
+type resolveCache struct {
+	BaseURL string  `json:"base_url"`
+	Agents  []Agent `json:"agents"`
+}
+
+// AmbiguousRefError is returned when an agent reference matches more than
+// one agent
+type AmbiguousRefError struct {
+	Ref        string
+	Candidates []Agent
+}
+
+// Error implements error, listing the candidates
+func (e *AmbiguousRefError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d agents:", e.Ref, len(e.Candidates))
+	for _, agent := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s  %-10s %s", agent.ID, agent.Status, agent.Name)
+	}
+	return b.String()
+}
+
+// ResolveAgentRef turns an agent reference into an agent ID. A reference is
+// a full agent ID, a unique prefix of one (with or without the "bc_"
+// prefix), an agent name, or one of RefLast and RefLastRunning. References
+// that match no agent are returned unchanged, so the API can report them.
+//
+// Full IDs are looked up directly. Other references are matched against
+// the agent list of KnownAgents; RefLastRunning always lists the agents
+// again, as it depends on their current status.
+func (c *Client) ResolveAgentRef(ctx context.Context, ref string) (string, error) {
+	if looksLikeAgentID(ref) {
+		agent, err := c.GetAgentStatusContext(ctx, ref)
+		switch {
+		case err == nil:
+			return agent.ID, nil
+		case IsNotFound(err), errors.Is(err, cache.ErrNotFound):
+			// Not a full ID, but possibly a prefix of one
+		default:
+			return "", err
+		}
+	}
+
+	// Statuses in the cached list may be out of date
+	agents, cached, err := c.resolveAgents(ctx, ref == RefLastRunning)
+	if err != nil {
+		return "", err
+	}
+	agentID, found, err := matchAgentRef(ref, agents)
+	if !found && cached {
+		// The agent may be newer than the cached list
+		if agents, _, err = c.resolveAgents(ctx, true); err != nil {
+			return "", err
+		}
+		agentID, _, err = matchAgentRef(ref, agents)
+	}
+	return agentID, err
+}
+
+// KnownAgents returns up to maxResolveAgents agents, newest first. The list
+// is kept in the cache for resolveCacheTTL, so that consecutive commands
+// and shell completions don't list the agents again; launching, stopping
+// or deleting an agent drops it.
+func (c *Client) KnownAgents(ctx context.Context) ([]Agent, error) {
+	agents, _, err := c.resolveAgents(ctx, false)
+	return agents, err
+}
+
+// looksLikeAgentID reports whether ref has the form of an agent ID rather
+// than a name or a bare prefix
+func looksLikeAgentID(ref string) bool {
+	return (strings.HasPrefix(ref, "bc_") || strings.HasPrefix(ref, "bc-")) && len(ref) > len("bc_")
+}
+
+// matchAgentRef resolves ref against agents. It reports whether ref
+// matched any agent; if not, ref is returned unchanged or, for RefLast and
+// RefLastRunning, with an error.
+func matchAgentRef(ref string, agents []Agent) (string, bool, error) {
+	switch ref {
+	case RefLast, RefLastRunning:
+		var latest *Agent
+		for i, agent := range agents {
+			if ref == RefLastRunning && agent.Status != StatusRunning {
+				continue
+			}
+			if latest == nil || agent.CreatedAt.After(latest.CreatedAt) {
+				latest = &agents[i]
+			}
+		}
+		if latest == nil {
+			if ref == RefLastRunning {
+				return "", false, fmt.Errorf("%s: no running agents", ref)
+			}
+			return "", false, fmt.Errorf("%s: no agents", ref)
+		}
+		return latest.ID, true, nil
+	}
+
+	// An exact ID always wins over prefixes and names
+	for _, agent := range agents {
+		if agent.ID == ref {
+			return ref, true, nil
+		}
+	}
+
+	var matches []Agent
+	for _, agent := range agents {
+		if strings.HasPrefix(agent.ID, ref) || strings.HasPrefix(agent.ID, "bc_"+ref) || strings.HasPrefix(agent.ID, "bc-"+ref) {
+			matches = append(matches, agent)
+		}
+	}
+	if len(matches) == 0 {
+		for _, agent := range agents {
+			if agent.Name != "" && strings.EqualFold(agent.Name, ref) {
+				matches = append(matches, agent)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return ref, false, nil
+	case 1:
+		return matches[0].ID, true, nil
+	default:
+		return "", true, &AmbiguousRefError{Ref: ref, Candidates: matches}
+	}
+}
+
+// resolveAgents returns the agents references are resolved against and
+// whether they were read from the cache. The list is fetched once per
+// Client and, online, reused from the cache while it is fresh; refresh
+// lists the agents from the API regardless.
+func (c *Client) resolveAgents(ctx context.Context, refresh bool) ([]Agent, bool, error) {
+	c.agentsMu.Lock()
+	defer c.agentsMu.Unlock()
+
+	if c.agents != nil && !(refresh && c.agentsCached) {
+		return c.agents, c.agentsCached, nil
+	}
+
+	if !refresh && !c.offline && c.cache != nil {
+		var cached resolveCache
+		fetchedAt, err := c.cache.Get(resolveCacheKey, &cached)
+		if err == nil && cached.BaseURL == c.BaseURL && time.Since(fetchedAt) < resolveCacheTTL && cached.Agents != nil {
+			c.agents, c.agentsCached = cached.Agents, true
+			return c.agents, true, nil
+		}
+	}
+
+	agents, err := c.listResolveAgents(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("error listing agents to resolve reference: %w", err)
+	}
+	if agents == nil {
+		agents = []Agent{}
+	}
+	c.agents, c.agentsCached = agents, false
+
+	if !c.offline && c.cache != nil {
+		// Best effort, like the rest of the cache
+		_ = c.cache.Put(resolveCacheKey, resolveCache{BaseURL: c.BaseURL, Agents: agents})
+	}
+	return agents, false, nil
+}
+
+// listResolveAgents lists up to maxResolveAgents agents. Unlike AllAgents
+// it doesn't save each agent in the cache, as the list is cached as a
+// whole.
+func (c *Client) listResolveAgents(ctx context.Context) ([]Agent, error) {
+	if c.offline {
+		return c.AllAgents(ctx, maxResolveAgents)
+	}
+
+	var agents []Agent
+	cursor := ""
+	for {
+		page, err := c.fetchAgentList(ctx, MaxPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		agents = append(agents, page.Agents...)
+
+		if len(agents) >= maxResolveAgents {
+			return agents[:maxResolveAgents], nil
+		}
+		if page.NextCursor == "" {
+			return agents, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// forgetResolveAgents drops the agent list of KnownAgents, after an agent
+// was launched, stopped or deleted
+func (c *Client) forgetResolveAgents() {
+	c.agentsMu.Lock()
+	c.agents, c.agentsCached = nil, false
+	c.agentsMu.Unlock()
+
+	if c.cache != nil && !c.offline {
+		_ = c.cache.Delete(resolveCacheKey)
+	}
+}
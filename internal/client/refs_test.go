@@ -0,0 +1,278 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/cache"
+)
+
+// agentServer is a fake API serving a fixed agent list and recording the
+// requests it receives
+type agentServer struct {
+	mu       sync.Mutex
+	agents   []string
+	stopped  map[string]bool
+	requests []string
+}
+
+// status returns the status the server reports for an agent
+func (s *agentServer) status(id string) string {
+	if s.stopped[id] {
+		return StatusCancelled
+	}
+	return StatusRunning
+}
+
+func (s *agentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/agents":
+		var items []string
+		for i, id := range s.agents {
+			items = append(items, fmt.Sprintf(`{"id":%q,"name":"Agent %d","status":%q,"createdAt":"2024-06-0%dT10:00:00Z"}`, id, i, s.status(id), i+1))
+		}
+		fmt.Fprintf(w, `{"agents":[%s]}`, strings.Join(items, ","))
+	case r.Method == http.MethodPost && r.URL.Path == "/agents":
+		id := fmt.Sprintf("bc_new%d", len(s.agents))
+		s.agents = append(s.agents, id)
+		fmt.Fprintf(w, `{"id":%q,"status":"CREATING"}`, id)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/stop"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/agents/"), "/stop")
+		if s.stopped == nil {
+			s.stopped = make(map[string]bool)
+		}
+		s.stopped[id] = true
+		fmt.Fprintf(w, `{"id":%q}`, id)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/agents/"):
+		id := strings.TrimPrefix(r.URL.Path, "/agents/")
+		for _, agent := range s.agents {
+			if agent == id {
+				fmt.Fprintf(w, `{"id":%q,"status":"RUNNING"}`, id)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":"agent not found"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// count returns how many requests matched method and path
+func (s *agentServer) count(request string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if r == request {
+			n++
+		}
+	}
+	return n
+}
+
+// newRefsClient returns a client for srv sharing store as its cache, as
+// consecutive commands do
+func newRefsClient(t *testing.T, url string, store *cache.Store) *Client {
+	t.Helper()
+	c, err := New("test-key", WithBaseURL(url), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithCache(store))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestResolveAgentRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "bc_abc123", want: "bc_abc123"},
+		{ref: "abc", want: "bc_abc123"},
+		{ref: "bc_abc", want: "bc_abc123"},
+		{ref: "agent 1", want: "bc_abd456"},
+		{ref: "ab", wantErr: true},
+		{ref: "zzz", want: "zzz"},
+		{ref: RefLast, want: "bc_xyz789"},
+	}
+
+	srv := &agentServer{agents: []string{"bc_abc123", "bc_abd456", "bc_xyz789"}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	c := newRefsClient(t, server.URL, cache.Open(t.TempDir()))
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := c.ResolveAgentRef(context.Background(), tt.ref)
+			if tt.wantErr {
+				var ambiguous *AmbiguousRefError
+				if !errors.As(err, &ambiguous) {
+					t.Fatalf("ResolveAgentRef(%q) error = %v, want AmbiguousRefError", tt.ref, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveAgentRef(%q): %v", tt.ref, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveAgentRef(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveFullIDDoesNotList(t *testing.T) {
+	srv := &agentServer{agents: []string{"bc_abc123"}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	c := newRefsClient(t, server.URL, cache.Open(t.TempDir()))
+
+	if _, err := c.ResolveAgentRef(context.Background(), "bc_abc123"); err != nil {
+		t.Fatalf("ResolveAgentRef: %v", err)
+	}
+	if n := srv.count("GET /agents"); n != 0 {
+		t.Errorf("listed agents %d times, want 0", n)
+	}
+	if n := srv.count("GET /agents/bc_abc123"); n != 1 {
+		t.Errorf("looked up the agent %d times, want 1", n)
+	}
+}
+
+func TestResolveReusesCachedList(t *testing.T) {
+	srv := &agentServer{agents: []string{"bc_abc123", "bc_xyz789"}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	store := cache.Open(t.TempDir())
+
+	// Each command has a client of its own
+	for _, ref := range []string{"abc", "xyz", "abc"} {
+		if _, err := newRefsClient(t, server.URL, store).ResolveAgentRef(context.Background(), ref); err != nil {
+			t.Fatalf("ResolveAgentRef(%q): %v", ref, err)
+		}
+	}
+	if n := srv.count("GET /agents"); n != 1 {
+		t.Errorf("listed agents %d times, want 1", n)
+	}
+
+	keys, err := store.Keys("agents")
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("resolving saved %d agents in the cache, want none", len(keys))
+	}
+}
+
+func TestResolveRefreshesCachedListOnMiss(t *testing.T) {
+	srv := &agentServer{agents: []string{"bc_abc123"}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	store := cache.Open(t.TempDir())
+
+	if _, err := newRefsClient(t, server.URL, store).ResolveAgentRef(context.Background(), "abc"); err != nil {
+		t.Fatalf("ResolveAgentRef: %v", err)
+	}
+
+	// Created elsewhere, e.g. in the web app
+	srv.mu.Lock()
+	srv.agents = append(srv.agents, "bc_web999")
+	srv.mu.Unlock()
+
+	got, err := newRefsClient(t, server.URL, store).ResolveAgentRef(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("ResolveAgentRef: %v", err)
+	}
+	if got != "bc_web999" {
+		t.Errorf("ResolveAgentRef(web) = %q, want bc_web999", got)
+	}
+	if n := srv.count("GET /agents"); n != 2 {
+		t.Errorf("listed agents %d times, want 2", n)
+	}
+}
+
+func TestLaunchInvalidatesCachedList(t *testing.T) {
+	srv := &agentServer{agents: []string{"bc_abc123"}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	store := cache.Open(t.TempDir())
+
+	if _, err := newRefsClient(t, server.URL, store).ResolveAgentRef(context.Background(), RefLast); err != nil {
+		t.Fatalf("ResolveAgentRef: %v", err)
+	}
+
+	launched, err := newRefsClient(t, server.URL, store).LaunchAgent(LaunchAgentRequest{})
+	if err != nil {
+		t.Fatalf("LaunchAgent: %v", err)
+	}
+
+	got, err := newRefsClient(t, server.URL, store).ResolveAgentRef(context.Background(), RefLast)
+	if err != nil {
+		t.Fatalf("ResolveAgentRef: %v", err)
+	}
+	if got != launched.ID {
+		t.Errorf("ResolveAgentRef(@last) = %q, want the launched agent %q", got, launched.ID)
+	}
+}
+
+func TestLastRunningIgnoresCachedStatus(t *testing.T) {
+	srv := &agentServer{agents: []string{"bc_abc123", "bc_xyz789"}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	store := cache.Open(t.TempDir())
+
+	// Fill the cache with both agents running
+	if _, err := newRefsClient(t, server.URL, store).ResolveAgentRef(context.Background(), "abc"); err != nil {
+		t.Fatalf("ResolveAgentRef: %v", err)
+	}
+
+	// Stopped elsewhere, e.g. in the web app
+	srv.mu.Lock()
+	srv.stopped = map[string]bool{"bc_xyz789": true}
+	srv.mu.Unlock()
+
+	got, err := newRefsClient(t, server.URL, store).ResolveAgentRef(context.Background(), RefLastRunning)
+	if err != nil {
+		t.Fatalf("ResolveAgentRef: %v", err)
+	}
+	if got != "bc_abc123" {
+		t.Errorf("ResolveAgentRef(@last-running) = %q, want the agent still running, bc_abc123", got)
+	}
+}
+
+func TestStopInvalidatesCachedList(t *testing.T) {
+	srv := &agentServer{agents: []string{"bc_abc123", "bc_xyz789"}}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	store := cache.Open(t.TempDir())
+
+	if _, err := newRefsClient(t, server.URL, store).KnownAgents(context.Background()); err != nil {
+		t.Fatalf("KnownAgents: %v", err)
+	}
+	if _, err := newRefsClient(t, server.URL, store).StopAgent("bc_xyz789"); err != nil {
+		t.Fatalf("StopAgent: %v", err)
+	}
+
+	agents, err := newRefsClient(t, server.URL, store).KnownAgents(context.Background())
+	if err != nil {
+		t.Fatalf("KnownAgents: %v", err)
+	}
+	for _, agent := range agents {
+		if agent.ID == "bc_xyz789" && agent.Status != StatusCancelled {
+			t.Errorf("stopped agent has status %s in the known agents, want %s", agent.Status, StatusCancelled)
+		}
+	}
+	if n := srv.count("GET /agents"); n != 2 {
+		t.Errorf("listed agents %d times, want 2", n)
+	}
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetry retries quickly so the tests don't wait on backoff
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+// newTestClient returns a client talking to a test server running handler
+func newTestClient(t *testing.T, policy RetryPolicy, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New("test-key", WithBaseURL(server.URL), WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+// flaky returns a handler that fails with status the given number of times
+// before answering with an agent, and a counter of the attempts it saw
+func flaky(failures int, status int, header http.Header) (http.HandlerFunc, *int32) {
+	var attempts int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if int(n) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"error":"try again"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"bc_1","status":"RUNNING"}`)
+	}, &attempts
+}
+
+// statusOf returns the HTTP status of an API error, or 0
+func statusOf(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func TestRetriesTransientStatuses(t *testing.T) {
+	for _, status := range []int{500, 502, 503, 504, 429} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			handler, attempts := flaky(2, status, nil)
+			c := newTestClient(t, fastRetry, handler)
+
+			agent, err := c.GetAgentStatusContext(context.Background(), "bc_1")
+			if err != nil {
+				t.Fatalf("GetAgentStatusContext: %v", err)
+			}
+			if agent.ID != "bc_1" {
+				t.Errorf("agent ID = %q, want bc_1", agent.ID)
+			}
+			if *attempts != 3 {
+				t.Errorf("attempts = %d, want 3", *attempts)
+			}
+		})
+	}
+}
+
+func TestGivesUpAfterMaxAttempts(t *testing.T) {
+	handler, attempts := flaky(10, http.StatusServiceUnavailable, nil)
+	c := newTestClient(t, fastRetry, handler)
+
+	_, err := c.GetAgentStatusContext(context.Background(), "bc_1")
+	if statusOf(err) != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want a 503 API error", err)
+	}
+	if *attempts != 3 {
+		t.Errorf("attempts = %d, want 3", *attempts)
+	}
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	handler, attempts := flaky(10, http.StatusNotFound, nil)
+	c := newTestClient(t, fastRetry, handler)
+
+	_, err := c.GetAgentStatusContext(context.Background(), "bc_1")
+	if !IsNotFound(err) {
+		t.Fatalf("err = %v, want not found", err)
+	}
+	if *attempts != 1 {
+		t.Errorf("attempts = %d, want 1", *attempts)
+	}
+}
+
+func TestPostRetriedOnlyWithIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		wantAttempts int32
+	}{
+		{name: "without key", wantAttempts: 1},
+		{name: "with key", key: "launch-1", wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			var keys []string
+			c := newTestClient(t, fastRetry, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("method = %s, want POST", r.Method)
+				}
+				keys = append(keys, r.Header.Get("Idempotency-Key"))
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				fmt.Fprint(w, `{"id":"bc_1"}`)
+			})
+
+			ctx := context.Background()
+			if tt.key != "" {
+				ctx = WithIdempotencyKey(ctx, tt.key)
+			}
+			_, err := c.AddFollowupContext(ctx, "bc_1", "continue")
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if tt.key == "" && statusOf(err) != http.StatusServiceUnavailable {
+				t.Errorf("err = %v, want a 503 API error", err)
+			}
+			if tt.key != "" {
+				if err != nil {
+					t.Errorf("AddFollowupContext: %v", err)
+				}
+				for i, key := range keys {
+					if key != tt.key {
+						t.Errorf("attempt %d Idempotency-Key = %q, want %q", i+1, key, tt.key)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "absent", value: ""},
+		{name: "seconds", value: "7", want: 7 * time.Second, ok: true},
+		{name: "zero seconds", value: "0", want: 0, ok: true},
+		{name: "negative seconds", value: "-1"},
+		{name: "http date", value: now.Add(30 * time.Second).UTC().Format(http.TimeFormat), want: 30 * time.Second, ok: true},
+		{name: "past http date", value: now.Add(-time.Hour).UTC().Format(http.TimeFormat), want: 0, ok: true},
+		{name: "garbage", value: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: make(http.Header)}
+			if tt.value != "" {
+				resp.Header.Set("Retry-After", tt.value)
+			}
+
+			got, ok := retryAfter(resp)
+			if ok != tt.ok {
+				t.Fatalf("retryAfter(%q) ok = %v, want %v", tt.value, ok, tt.ok)
+			}
+			// HTTP dates have a resolution of one second
+			if diff := got - tt.want; diff > 0 || diff < -2*time.Second {
+				t.Errorf("retryAfter(%q) = %s, want about %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterIsHonored(t *testing.T) {
+	header := http.Header{"Retry-After": {"1"}}
+	handler, attempts := flaky(1, http.StatusTooManyRequests, header)
+	c := newTestClient(t, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Second}, handler)
+
+	start := time.Now()
+	if _, err := c.GetAgentStatusContext(context.Background(), "bc_1"); err != nil {
+		t.Fatalf("GetAgentStatusContext: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s of Retry-After", elapsed)
+	}
+	if *attempts != 2 {
+		t.Errorf("attempts = %d, want 2", *attempts)
+	}
+}
+
+func TestRetryAfterAboveMaxBackoffEndsRetries(t *testing.T) {
+	for _, value := range []string{"60", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)} {
+		t.Run(value, func(t *testing.T) {
+			header := http.Header{"Retry-After": {value}}
+			handler, attempts := flaky(10, http.StatusServiceUnavailable, header)
+			c := newTestClient(t, fastRetry, handler)
+
+			start := time.Now()
+			_, err := c.GetAgentStatusContext(context.Background(), "bc_1")
+			if statusOf(err) != http.StatusServiceUnavailable {
+				t.Fatalf("err = %v, want a 503 API error", err)
+			}
+			if *attempts != 1 {
+				t.Errorf("attempts = %d, want 1", *attempts)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("gave up after %s, want immediately", elapsed)
+			}
+		})
+	}
+}
+
+func TestCancelStopsWaitBetweenAttempts(t *testing.T) {
+	handler, attempts := flaky(10, http.StatusServiceUnavailable, nil)
+	c := newTestClient(t, RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Second, MaxBackoff: 10 * time.Second}, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := c.GetAgentStatusContext(ctx, "bc_1")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("returned after %s, want soon after cancellation", elapsed)
+	}
+	if *attempts != 1 {
+		t.Errorf("attempts = %d, want 1", *attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		max    time.Duration
+	}{
+		{name: "first retry", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, retry: 1, max: 100 * time.Millisecond},
+		{name: "doubles", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, retry: 3, max: 400 * time.Millisecond},
+		{name: "capped", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 150 * time.Millisecond}, retry: 5, max: 150 * time.Millisecond},
+		{name: "uncapped", policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond}, retry: 4, max: 800 * time.Millisecond},
+		{name: "no backoff", policy: RetryPolicy{}, retry: 3, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got := tt.policy.backoff(tt.retry)
+				// Equal jitter keeps the delay between half and all of it
+				if got < tt.max/2 || got > tt.max {
+					t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.retry, got, tt.max/2, tt.max)
+				}
+			}
+		})
+	}
+}
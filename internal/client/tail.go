@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// ConversationTail follows the conversation of an agent, returning only the
+// messages that were not returned before. Polling it is cheap: an
+// unchanged conversation is revalidated with its ETag rather than
+// downloaded again. It is safe for concurrent use.
+type ConversationTail struct {
+	client  *Client
+	agentID string
+
+	mu             sync.Mutex
+	seen           map[string]bool
+	conversationID string
+	length         int
+}
+
+// TailConversation returns a ConversationTail for the conversation of
+// agentID. The first call to Next returns every message.
+func (c *Client) TailConversation(agentID string) *ConversationTail {
+	return &ConversationTail{client: c, agentID: agentID, seen: make(map[string]bool)}
+}
+
+// AgentID returns the agent whose conversation is followed
+func (t *ConversationTail) AgentID() string {
+	return t.agentID
+}
+
+// Next fetches the conversation and returns the messages whose IDs were not
+// returned by earlier calls, in conversation order. When the conversation
+// is replaced by one with another ID, every message of the new one is
+// returned.
+func (t *ConversationTail) Next(ctx context.Context) ([]Message, error) {
+	conversation, err := t.client.GetAgentConversationContext(ctx, t.agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if conversation.ID != t.conversationID {
+		if t.conversationID != "" {
+			t.seen = make(map[string]bool)
+		}
+		t.conversationID = conversation.ID
+	}
+	if len(conversation.Messages) < t.length {
+		// Messages added where removed ones used to be are new
+		for i := len(conversation.Messages); i < t.length; i++ {
+			delete(t.seen, "#"+strconv.Itoa(i))
+		}
+	}
+	t.length = len(conversation.Messages)
+
+	var messages []Message
+	for i, message := range conversation.Messages {
+		key := message.ID
+		if key == "" {
+			// Messages without an ID are told apart by their position
+			key = "#" + strconv.Itoa(i)
+		}
+		if t.seen[key] {
+			continue
+		}
+		t.seen[key] = true
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// Skip marks the messages currently in the conversation as seen, so that
+// Next only returns messages added afterwards
+func (t *ConversationTail) Skip(ctx context.Context) error {
+	_, err := t.Next(ctx)
+	return err
+}
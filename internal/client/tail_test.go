@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestConversationTail(t *testing.T) {
+	hello := Message{ID: "m1", Text: "hello"}
+	working := Message{ID: "m2", Text: "working"}
+	done := Message{ID: "m3", Text: "done"}
+
+	// Each step sets the conversation the server returns, then calls Next
+	type step struct {
+		id       string
+		etag     string
+		messages []Message
+		want     []string
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "new messages only",
+			steps: []step{
+				{id: "conv_1", etag: `"v1"`, messages: []Message{hello}, want: []string{"hello"}},
+				{id: "conv_1", etag: `"v2"`, messages: []Message{hello, working}, want: []string{"working"}},
+				{id: "conv_1", etag: `"v3"`, messages: []Message{hello, working, done}, want: []string{"done"}},
+			},
+		},
+		{
+			name: "unchanged",
+			steps: []step{
+				{id: "conv_1", etag: `"v1"`, messages: []Message{hello, working}, want: []string{"hello", "working"}},
+				{id: "conv_1", etag: `"v1"`, messages: []Message{hello, working}, want: nil},
+				{id: "conv_1", etag: `"v1"`, messages: []Message{hello, working}, want: nil},
+			},
+		},
+		{
+			name: "shrinks",
+			steps: []step{
+				{id: "conv_1", etag: `"v1"`, messages: []Message{hello, working}, want: []string{"hello", "working"}},
+				{id: "conv_1", etag: `"v2"`, messages: []Message{hello}, want: nil},
+				{id: "conv_1", etag: `"v3"`, messages: []Message{hello, done}, want: []string{"done"}},
+			},
+		},
+		{
+			name: "shrinks without message IDs",
+			steps: []step{
+				{id: "conv_1", etag: `"v1"`, messages: []Message{{Text: "hello"}, {Text: "working"}}, want: []string{"hello", "working"}},
+				{id: "conv_1", etag: `"v2"`, messages: []Message{{Text: "hello"}}, want: nil},
+				{id: "conv_1", etag: `"v3"`, messages: []Message{{Text: "hello"}, {Text: "done"}}, want: []string{"done"}},
+			},
+		},
+		{
+			name: "replaced",
+			steps: []step{
+				{id: "conv_1", etag: `"v1"`, messages: []Message{hello, working}, want: []string{"hello", "working"}},
+				{id: "conv_2", etag: `"w1"`, messages: []Message{hello, done}, want: []string{"hello", "done"}},
+				{id: "conv_2", etag: `"w2"`, messages: []Message{hello, done, working}, want: []string{"working"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &conversationServer{}
+			c := newTestClient(t, RetryPolicy{MaxAttempts: 1}, srv.ServeHTTP)
+			tail := c.TailConversation("bc_abc123")
+
+			for i, s := range tt.steps {
+				srv.set(s.id, s.etag, s.messages...)
+				messages, err := tail.Next(context.Background())
+				if err != nil {
+					t.Fatalf("step %d: Next: %v", i, err)
+				}
+				if got := texts(messages); !reflect.DeepEqual(got, s.want) {
+					t.Errorf("step %d: Next = %v, want %v", i, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestConversationTailSkip(t *testing.T) {
+	srv := &conversationServer{}
+	srv.set("conv_1", `"v1"`, Message{ID: "m1", Text: "hello"})
+	c := newTestClient(t, RetryPolicy{MaxAttempts: 1}, srv.ServeHTTP)
+	tail := c.TailConversation("bc_abc123")
+
+	if err := tail.Skip(context.Background()); err != nil {
+		t.Fatalf("Skip: %v", err)
+	}
+	srv.set("conv_1", `"v2"`, Message{ID: "m1", Text: "hello"}, Message{ID: "m2", Text: "done"})
+
+	messages, err := tail.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if got, want := texts(messages), []string{"done"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Next after Skip = %v, want %v", got, want)
+	}
+}
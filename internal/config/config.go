@@ -1,45 +1,377 @@
 package config
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
 var (
 	ErrAPIKeyNotSet = errors.New("API key not set. Please run 'cursor-cli init' or set CURSOR_API_KEY environment variable")
+
+	// profileNamePattern restricts profile names to characters that are
+	// safe as viper key segments
+	profileNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
 )
 
-// GetAPIKey returns the API key from config or environment
+// GetAPIKey returns the API key from the --api-key flag, the CURSOR_API_KEY
+// environment variable or the active profile, in that order. A profile
+// either holds the key itself (api_key) or a reference to a secret backend
+// (api_key_ref).
 func GetAPIKey() (string, error) {
-	// First check if it's set via flag/config
-	apiKey := viper.GetString("api_key")
-	if apiKey != "" {
-		return apiKey, nil
+	if r, ok := resolveOverride("api_key"); ok {
+		return cast.ToString(r.Value), nil
 	}
 
-	// Then check environment variable
-	apiKey = os.Getenv("CURSOR_API_KEY")
-	if apiKey != "" {
+	// Resolve the secret the active profile refers to
+	if ref := ProfileString("api_key_ref"); ref != "" {
+		return resolveSecretRef(ref)
+	}
+
+	// Fall back to a plaintext key stored in the config file
+	if apiKey := GetString("api_key"); apiKey != "" {
 		return apiKey, nil
 	}
 
+	if profile := ActiveProfile(); profile != DefaultProfile {
+		return "", fmt.Errorf("%w (profile %q)", ErrAPIKeyNotSet, profile)
+	}
 	return "", ErrAPIKeyNotSet
 }
 
-// SaveAPIKey saves the API key to the config file
-func SaveAPIKey(apiKey string) error {
-	viper.Set("api_key", apiKey)
+// SaveAPIKey saves the API key for the active profile in the named secret
+// backend and stores a reference to it in the config file. BackendPlain
+// writes the key itself to the config file and BackendAuto picks the
+// keyring when available, falling back to plain. It returns the name of
+// the backend used.
+func SaveAPIKey(apiKey, backend string) (string, error) {
+	return SaveAPIKeyWithHelper(apiKey, backend, "")
+}
+
+// SaveAPIKeyWithHelper is like SaveAPIKey, but also sets the credential
+// helper command of the profile when helper is not empty. The helper is
+// written to the config file together with the key reference, so nothing is
+// saved if storing the key fails.
+func SaveAPIKeyWithHelper(apiKey, backend, helper string) (string, error) {
+	profile := ActiveProfile()
+
+	if backend == "" || backend == BackendAuto {
+		backend = BackendPlain
+		if (keyringBackend{}).Available() {
+			backend = BackendKeyring
+		}
+	}
+
+	// setHelper adds the credential helper to the config file update
+	setHelper := func(doc map[string]interface{}) {
+		if helper != "" {
+			setPath(doc, []string{"profiles", profile, "credential_helper"}, helper)
+		}
+	}
+
+	if backend == BackendPlain {
+		if err := forgetPreviousSecret(profile, ""); err != nil {
+			return "", err
+		}
+		viper.Set(profileKey(profile, "api_key"), apiKey)
+		viper.Set(profileKey(profile, "api_key_ref"), "")
+		if helper != "" {
+			viper.Set(profileKey(profile, "credential_helper"), helper)
+		}
+		return backend, update(func(doc map[string]interface{}) {
+			setPath(doc, []string{"profiles", profile, "api_key"}, apiKey)
+			deletePath(doc, []string{"profiles", profile, "api_key_ref"})
+			setHelper(doc)
+			setCurrentProfile(doc, profile)
+		})
+	}
 
-	// Get home directory
-	home, err := os.UserHomeDir()
+	var store SecretBackend
+	if backend == BackendHelper && helper != "" {
+		store = helperBackend{command: helper}
+	} else {
+		var err error
+		if store, err = Backend(backend); err != nil {
+			return "", err
+		}
+	}
+	if !store.Available() {
+		return "", fmt.Errorf("secret backend %q is not available on this system", backend)
+	}
+	if err := store.Set(profile, apiKey); err != nil {
+		return "", fmt.Errorf("error storing API key in %s: %w", backend, err)
+	}
+
+	ref := backend + ":" + profile
+	if err := forgetPreviousSecret(profile, ref); err != nil {
+		// Keep the previous reference working and don't leave a second copy
+		_ = store.Delete(profile)
+		return "", err
+	}
+
+	viper.Set(profileKey(profile, "api_key_ref"), ref)
+	viper.Set(profileKey(profile, "api_key"), "")
+	if helper != "" {
+		viper.Set(profileKey(profile, "credential_helper"), helper)
+	}
+	return backend, update(func(doc map[string]interface{}) {
+		setPath(doc, []string{"profiles", profile, "api_key_ref"}, ref)
+		deletePath(doc, []string{"profiles", profile, "api_key"})
+		setHelper(doc)
+		setCurrentProfile(doc, profile)
+	})
+}
+
+// forgetPreviousSecret deletes the API key of profile from the backend its
+// api_key_ref points to, so that switching backends leaves no copy behind.
+// Nothing is deleted if the reference equals keep, where the key is being
+// stored now.
+func forgetPreviousSecret(profile, keep string) error {
+	previous := viper.GetString(profileKey(profile, "api_key_ref"))
+	if previous == "" || previous == keep {
+		return nil
+	}
+
+	name, ref, ok := strings.Cut(previous, ":")
+	if !ok || ref == "" {
+		// Nothing can be stored behind an invalid reference
+		return nil
+	}
+	backend, err := Backend(name)
 	if err != nil {
+		return fmt.Errorf("error removing the previous API key from %s: %w", name, err)
+	}
+	if err := backend.Delete(ref); err != nil {
+		return fmt.Errorf("error removing the previous API key from %s: %w", name, err)
+	}
+	return nil
+}
+
+// ActiveProfile returns the selected profile: the --profile flag, then the
+// CURSOR_PROFILE environment variable, then current_profile from the config
+// file, and finally DefaultProfile
+func ActiveProfile() string {
+	if r, ok := resolveOverride("profile"); ok {
+		return strings.ToLower(cast.ToString(r.Value))
+	}
+	if profile := viper.GetString("current_profile"); profile != "" {
+		return strings.ToLower(profile)
+	}
+	return DefaultProfile
+}
+
+// ValidateProfileOverride checks the profile selected with --profile or
+// CURSOR_PROFILE. Its name becomes part of config keys and cache paths, so
+// it must be checked before anything uses it.
+func ValidateProfileOverride() error {
+	r, ok := resolveOverride("profile")
+	if !ok {
+		return nil
+	}
+	if err := ValidateProfileName(strings.ToLower(cast.ToString(r.Value))); err != nil {
+		return fmt.Errorf("%w (from %s)", err, r.Origin)
+	}
+	return nil
+}
+
+// ProfileString returns a setting of the active profile
+func ProfileString(key string) string {
+	return viper.GetString(profileKey(ActiveProfile(), key))
+}
+
+// Profiles returns the names of all profiles in the config file, sorted
+func Profiles() []string {
+	var names []string
+	for name := range viper.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateProfileName checks that name can be used as a profile name
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// UseProfile makes name the current profile in the config file
+func UseProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
 		return err
 	}
 
-	// Set config file path
-	configFile := home + "/.cursor-cli.yaml"
+	exists := false
+	for _, profile := range Profiles() {
+		if profile == name {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return fmt.Errorf("profile %q does not exist. Create it with 'cursor-cli --profile %s init'", name, name)
+	}
+
+	viper.Set("current_profile", name)
+	return update(func(doc map[string]interface{}) {
+		doc["current_profile"] = name
+	})
+}
 
-	return viper.WriteConfigAs(configFile)
+// MigrateLegacy moves the settings of a config file written before profiles
+// existed (a top-level api_key) into the default profile. It reports whether
+// the file was changed.
+func MigrateLegacy() (bool, error) {
+	if viper.ConfigFileUsed() == "" || viper.IsSet("profiles") || !viper.IsSet("api_key") {
+		return false, nil
+	}
+
+	doc, err := load()
+	if err != nil {
+		return false, err
+	}
+	apiKey, ok := doc["api_key"]
+	if !ok {
+		// Set through the flag or environment rather than the file
+		return false, nil
+	}
+
+	delete(doc, "api_key")
+	setPath(doc, []string{"profiles", DefaultProfile, "api_key"}, apiKey)
+	setCurrentProfile(doc, DefaultProfile)
+	if err := save(doc); err != nil {
+		return false, err
+	}
+
+	return true, viper.ReadInConfig()
+}
+
+// Path returns the config file in use, or $XDG_CONFIG_HOME/cursor-cli/config.yaml
+// if none was found
+func Path() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// profileKey returns the viper key of a setting inside a profile
+func profileKey(profile, key string) string {
+	return "profiles." + profile + "." + key
+}
+
+// update applies fn to the config file document and writes it back.
+// Editing the file directly, rather than through viper.WriteConfig, keeps
+// flag values and defaults out of the file.
+func update(fn func(doc map[string]interface{})) error {
+	doc, err := load()
+	if err != nil {
+		return err
+	}
+	fn(doc)
+	return save(doc)
+}
+
+// load reads the config file as a generic document. A missing file yields
+// an empty document.
+func load() (map[string]interface{}, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]interface{})
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+	return doc, nil
+}
+
+// save writes the document to the config file, readable only by the user
+func save(doc map[string]interface{}) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
+// setPath sets a nested value in doc, creating intermediate maps as needed
+func setPath(doc map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := doc[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			doc[key] = next
+		}
+		doc = next
+	}
+	doc[path[len(path)-1]] = value
+}
+
+// deletePath removes a nested value from doc, if present
+func deletePath(doc map[string]interface{}, path []string) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := doc[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		doc = next
+	}
+	delete(doc, path[len(path)-1])
+}
+
+// setCurrentProfile records profile as current_profile unless one is
+// already selected
+func setCurrentProfile(doc map[string]interface{}, profile string) {
+	if _, ok := doc["current_profile"]; !ok {
+		doc["current_profile"] = profile
+	}
 }
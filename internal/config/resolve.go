@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Source is where the value of a setting was found. Settings are resolved
+// in this order of precedence: a command-line flag, an environment
+// variable, the active profile in the config file, the top level of the
+// config file and finally the built-in default.
+type Source string
+
+const (
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
+	SourceProfile Source = "profile"
+	SourceFile    Source = "file"
+	SourceDefault Source = "default"
+)
+
+// Resolved is the value of a setting together with where it came from
+type Resolved struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source Source      `json:"source"`
+
+	// Origin names the flag, environment variable, profile or file the
+	// value was taken from
+	Origin string `json:"origin,omitempty"`
+}
+
+var (
+	// flags maps settings to the command-line flags overriding them
+	flags = make(map[string]*pflag.Flag)
+
+	// defaults holds the built-in value of settings
+	defaults = make(map[string]interface{})
+)
+
+// BindFlag makes flag override the setting key when it is given on the
+// command line
+func BindFlag(key string, flag *pflag.Flag) {
+	flags[key] = flag
+}
+
+// SetDefault sets the value of key used when nothing else sets it
+func SetDefault(key string, value interface{}) {
+	defaults[key] = value
+}
+
+// EnvName returns the environment variable overriding key, e.g.
+// CURSOR_API_KEY for api_key and CURSOR_RETRY_MAX_ATTEMPTS for
+// retry.max_attempts
+func EnvName(key string) string {
+	return "CURSOR_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Resolve returns the value of key from the first source that sets it
+func Resolve(key string) (Resolved, bool) {
+	if r, ok := resolveOverride(key); ok {
+		return r, true
+	}
+
+	profile := ActiveProfile()
+	if value := viper.Get(profileKey(profile, key)); value != nil {
+		return Resolved{Key: key, Value: value, Source: SourceProfile, Origin: profile}, true
+	}
+
+	if value := viper.Get(key); value != nil {
+		return Resolved{Key: key, Value: value, Source: SourceFile, Origin: viper.ConfigFileUsed()}, true
+	}
+
+	if value, ok := defaults[key]; ok {
+		return Resolved{Key: key, Value: value, Source: SourceDefault}, true
+	}
+	return Resolved{Key: key}, false
+}
+
+// resolveOverride returns the value of key from its flag or environment
+// variable, the sources that take precedence over the config file
+func resolveOverride(key string) (Resolved, bool) {
+	if flag, ok := flags[key]; ok && flag.Changed {
+		return Resolved{Key: key, Value: flag.Value.String(), Source: SourceFlag, Origin: "--" + flag.Name}, true
+	}
+
+	env := EnvName(key)
+	if value, ok := os.LookupEnv(env); ok && value != "" {
+		return Resolved{Key: key, Value: value, Source: SourceEnv, Origin: env}, true
+	}
+	return Resolved{Key: key}, false
+}
+
+// IsSet reports whether any source sets key
+func IsSet(key string) bool {
+	_, ok := Resolve(key)
+	return ok
+}
+
+// GetString returns the resolved value of key as a string
+func GetString(key string) string {
+	r, _ := Resolve(key)
+	return cast.ToString(r.Value)
+}
+
+// GetBool returns the resolved value of key as a bool
+func GetBool(key string) bool {
+	r, _ := Resolve(key)
+	return cast.ToBool(r.Value)
+}
+
+// GetInt returns the resolved value of key as an int
+func GetInt(key string) int {
+	r, _ := Resolve(key)
+	return cast.ToInt(r.Value)
+}
+
+// GetDuration returns the resolved value of key as a duration
+func GetDuration(key string) time.Duration {
+	r, _ := Resolve(key)
+	return cast.ToDuration(r.Value)
+}
+
+// GetStringMapString returns the resolved value of key as a map
+func GetStringMapString(key string) map[string]string {
+	r, _ := Resolve(key)
+	return cast.ToStringMapString(r.Value)
+}
+
+// Report returns every known setting that is set, with where its value
+// comes from. Secrets are redacted.
+func Report() []Resolved {
+	var report []Resolved
+	for _, setting := range Settings {
+		r, ok := Resolve(setting.Key)
+		if !ok {
+			continue
+		}
+		if setting.Secret {
+			r.Value = Redact(cast.ToString(r.Value))
+		}
+		report = append(report, r)
+	}
+	return report
+}
+
+// Load reads the config file at path or, if path is empty, the first one
+// found of $XDG_CONFIG_HOME/cursor-cli/config.yaml and ~/.cursor-cli.yaml.
+// A missing file is not an error; it is created on the first write.
+func Load(path string) error {
+	if path == "" {
+		path = findConfigFile()
+	}
+	if path == "" {
+		return nil
+	}
+
+	viper.SetConfigFile(path)
+	viper.SetConfigType("yaml")
+	if err := viper.ReadInConfig(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return nil
+}
+
+// findConfigFile returns the first existing config file in the default
+// locations, or "" if there is none
+func findConfigFile() string {
+	var candidates []string
+	if dir, err := configDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, "config.yaml"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".cursor-cli.yaml"))
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// configDir returns the cursor-cli directory under $XDG_CONFIG_HOME, which
+// defaults to ~/.config
+func configDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, "cursor-cli"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "cursor-cli"), nil
+}
@@ -0,0 +1,463 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// testEnv is an isolated home directory with its own viper instance, flags
+// and defaults
+type testEnv struct {
+	t     *testing.T
+	home  string
+	xdg   string
+	flags *pflag.FlagSet
+}
+
+// newTestEnv points HOME and XDG_CONFIG_HOME at a temporary directory,
+// clears the environment variables the tests use and resets the package
+// state, restoring it when the test ends
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	home := t.TempDir()
+	env := &testEnv{
+		t:     t,
+		home:  home,
+		xdg:   filepath.Join(home, "xdg"),
+		flags: pflag.NewFlagSet("test", pflag.ContinueOnError),
+	}
+
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", env.xdg)
+	for _, name := range []string{"CURSOR_API_KEY", "CURSOR_PROFILE", "CURSOR_TIMEOUT"} {
+		t.Setenv(name, "")
+	}
+
+	savedFlags, savedDefaults := flags, defaults
+	flags = make(map[string]*pflag.Flag)
+	defaults = make(map[string]interface{})
+	viper.Reset()
+	t.Cleanup(func() {
+		flags, defaults = savedFlags, savedDefaults
+		viper.Reset()
+	})
+
+	env.flags.String("api-key", "", "")
+	env.flags.String("profile", "", "")
+	env.flags.String("request-timeout", "", "")
+	BindFlag("api_key", env.flags.Lookup("api-key"))
+	BindFlag("profile", env.flags.Lookup("profile"))
+	BindFlag("timeout", env.flags.Lookup("request-timeout"))
+	SetDefault("timeout", 30*time.Second)
+	return env
+}
+
+// setFlag sets a flag as if it was given on the command line
+func (e *testEnv) setFlag(name, value string) {
+	e.t.Helper()
+	if err := e.flags.Set(name, value); err != nil {
+		e.t.Fatalf("setting --%s: %v", name, err)
+	}
+}
+
+// writeFile writes a file below the home directory and returns its path
+func (e *testEnv) writeFile(name, content string) string {
+	e.t.Helper()
+	path := filepath.Join(e.home, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		e.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		e.t.Fatal(err)
+	}
+	return path
+}
+
+// load writes content as the XDG config file and reads it
+func (e *testEnv) load(content string) string {
+	e.t.Helper()
+	path := e.writeFile(filepath.Join("xdg", "cursor-cli", "config.yaml"), content)
+	if err := Load(""); err != nil {
+		e.t.Fatalf("Load: %v", err)
+	}
+	return path
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	const (
+		topLevel = "timeout: 20s\n"
+		profile  = "profiles:\n  default:\n    timeout: 10s\n"
+	)
+
+	tests := []struct {
+		name       string
+		file       string
+		env        string
+		flag       string
+		want       string
+		wantSource Source
+		wantOrigin string
+	}{
+		{name: "default", want: "30s", wantSource: SourceDefault},
+		{name: "top-level file", file: topLevel, want: "20s", wantSource: SourceFile},
+		{name: "profile", file: topLevel + profile, want: "10s", wantSource: SourceProfile, wantOrigin: DefaultProfile},
+		{name: "env", file: topLevel + profile, env: "5s", want: "5s", wantSource: SourceEnv, wantOrigin: "CURSOR_TIMEOUT"},
+		{name: "flag", file: topLevel + profile, env: "5s", flag: "1s", want: "1s", wantSource: SourceFlag, wantOrigin: "--request-timeout"},
+		{name: "flag without env", file: profile, flag: "1s", want: "1s", wantSource: SourceFlag, wantOrigin: "--request-timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			wantOrigin := tt.wantOrigin
+			if tt.file != "" {
+				path := env.load(tt.file)
+				if tt.wantSource == SourceFile {
+					wantOrigin = path
+				}
+			}
+			if tt.env != "" {
+				t.Setenv("CURSOR_TIMEOUT", tt.env)
+			}
+			if tt.flag != "" {
+				env.setFlag("request-timeout", tt.flag)
+			}
+
+			r, ok := Resolve("timeout")
+			if !ok {
+				t.Fatal("Resolve(timeout) found no value")
+			}
+			if r.Source != tt.wantSource {
+				t.Errorf("source = %s, want %s", r.Source, tt.wantSource)
+			}
+			if r.Origin != wantOrigin {
+				t.Errorf("origin = %q, want %q", r.Origin, wantOrigin)
+			}
+			if got := GetDuration("timeout").String(); got != tt.want {
+				t.Errorf("GetDuration(timeout) = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveUnset(t *testing.T) {
+	newTestEnv(t)
+
+	if r, ok := Resolve("base_url"); ok {
+		t.Errorf("Resolve(base_url) = %+v, want nothing", r)
+	}
+	if IsSet("base_url") {
+		t.Error("IsSet(base_url) = true, want false")
+	}
+}
+
+func TestResolveOverride(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		flag       string
+		wantOK     bool
+		wantSource Source
+		want       string
+	}{
+		{name: "neither"},
+		{name: "empty env is unset", env: ""},
+		{name: "env", env: "env-key", wantOK: true, wantSource: SourceEnv, want: "env-key"},
+		{name: "flag", flag: "flag-key", wantOK: true, wantSource: SourceFlag, want: "flag-key"},
+		{name: "flag over env", env: "env-key", flag: "flag-key", wantOK: true, wantSource: SourceFlag, want: "flag-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			// The config file never counts as an override
+			env.load("api_key: file-key\n")
+			t.Setenv("CURSOR_API_KEY", tt.env)
+			if tt.flag != "" {
+				env.setFlag("api-key", tt.flag)
+			}
+
+			r, ok := resolveOverride("api_key")
+			if ok != tt.wantOK {
+				t.Fatalf("resolveOverride(api_key) ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if r.Source != tt.wantSource || r.Value != tt.want {
+				t.Errorf("resolveOverride(api_key) = %+v, want %q from %s", r, tt.want, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestActiveProfile(t *testing.T) {
+	const file = `current_profile: work
+profiles:
+  default:
+    timeout: 1s
+  work:
+    timeout: 2s
+  staging:
+    timeout: 3s
+  ci:
+    timeout: 4s
+`
+
+	tests := []struct {
+		name        string
+		file        string
+		env         string
+		flag        string
+		want        string
+		wantTimeout string
+	}{
+		{name: "no config", want: DefaultProfile, wantTimeout: "30s"},
+		{name: "current_profile", file: file, want: "work", wantTimeout: "2s"},
+		{name: "CURSOR_PROFILE over current_profile", file: file, env: "staging", want: "staging", wantTimeout: "3s"},
+		{name: "--profile over CURSOR_PROFILE", file: file, env: "staging", flag: "ci", want: "ci", wantTimeout: "4s"},
+		{name: "--profile over current_profile", file: file, flag: "ci", want: "ci", wantTimeout: "4s"},
+		{name: "case insensitive", file: file, env: "STAGING", want: "staging", wantTimeout: "3s"},
+		{name: "unknown profile", file: file, flag: "other", want: "other", wantTimeout: "30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			if tt.file != "" {
+				env.load(tt.file)
+			}
+			if tt.env != "" {
+				t.Setenv("CURSOR_PROFILE", tt.env)
+			}
+			if tt.flag != "" {
+				env.setFlag("profile", tt.flag)
+			}
+
+			if got := ActiveProfile(); got != tt.want {
+				t.Errorf("ActiveProfile() = %q, want %q", got, tt.want)
+			}
+			if got := GetDuration("timeout").String(); got != tt.wantTimeout {
+				t.Errorf("GetDuration(timeout) = %s, want %s from the active profile", got, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestGetAPIKey(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the credential helper used here is a shell script")
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		env     string
+		flag    string
+		profile string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "flag",
+			file: "profiles:\n  default:\n    api_key: file-key\n",
+			env:  "env-key", flag: "flag-key",
+			want: "flag-key",
+		},
+		{
+			name: "env",
+			file: "profiles:\n  default:\n    api_key: file-key\n",
+			env:  "env-key",
+			want: "env-key",
+		},
+		{
+			name: "api_key_ref over plaintext api_key",
+			file: "profiles:\n  default:\n    api_key: file-key\n    api_key_ref: helper:default\n    credential_helper: sh HELPER\n",
+			want: "ref-key-default",
+		},
+		{
+			name:    "api_key_ref",
+			file:    "profiles:\n  work:\n    api_key_ref: helper:work\ncredential_helper: sh HELPER\n",
+			profile: "work",
+			want:    "ref-key-work",
+		},
+		{
+			name: "plaintext api_key",
+			file: "profiles:\n  default:\n    api_key: file-key\n",
+			want: "file-key",
+		},
+		{
+			name: "legacy top-level api_key",
+			file: "api_key: legacy-key\n",
+			want: "legacy-key",
+		},
+		{
+			name:    "invalid api_key_ref",
+			file:    "profiles:\n  default:\n    api_key: file-key\n    api_key_ref: helper\n",
+			wantErr: "invalid api_key_ref",
+		},
+		{
+			name:    "not set",
+			wantErr: ErrAPIKeyNotSet.Error(),
+		},
+		{
+			name:    "not set in profile",
+			file:    "profiles:\n  default:\n    api_key: file-key\n",
+			profile: "work",
+			wantErr: `(profile "work")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			helper := env.writeFile("helper.sh", `read line
+if [ "$1" = get ]; then echo "ref-key-${line#profile=}"; fi
+`)
+			if tt.file != "" {
+				env.load(strings.ReplaceAll(tt.file, "HELPER", helper))
+			}
+			if tt.env != "" {
+				t.Setenv("CURSOR_API_KEY", tt.env)
+			}
+			if tt.flag != "" {
+				env.setFlag("api-key", tt.flag)
+			}
+			if tt.profile != "" {
+				env.setFlag("profile", tt.profile)
+			}
+
+			got, err := GetAPIKey()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetAPIKey() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				if tt.wantErr == ErrAPIKeyNotSet.Error() && !errors.Is(err, ErrAPIKeyNotSet) {
+					t.Errorf("GetAPIKey() error = %v, want ErrAPIKeyNotSet", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAPIKey: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetAPIKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigFileDiscovery(t *testing.T) {
+	const (
+		xdgFile    = "xdg/cursor-cli/config.yaml"
+		dotConfig  = ".config/cursor-cli/config.yaml"
+		legacyFile = ".cursor-cli.yaml"
+	)
+
+	tests := []struct {
+		name     string
+		files    []string
+		xdg      string
+		want     string
+		wantPath string
+	}{
+		{name: "none", wantPath: xdgFile},
+		{name: "xdg", files: []string{xdgFile}, want: xdgFile, wantPath: xdgFile},
+		{name: "legacy", files: []string{legacyFile}, want: legacyFile, wantPath: legacyFile},
+		{name: "xdg over legacy", files: []string{xdgFile, legacyFile}, want: xdgFile, wantPath: xdgFile},
+		{name: "unset XDG_CONFIG_HOME", files: []string{dotConfig, legacyFile}, xdg: "-", want: dotConfig, wantPath: dotConfig},
+		{name: "relative XDG_CONFIG_HOME", files: []string{xdgFile, legacyFile}, xdg: "xdg", want: legacyFile, wantPath: legacyFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			switch tt.xdg {
+			case "":
+			case "-":
+				t.Setenv("XDG_CONFIG_HOME", "")
+			default:
+				t.Setenv("XDG_CONFIG_HOME", tt.xdg)
+			}
+			for _, name := range tt.files {
+				env.writeFile(name, "timeout: 5s\n")
+			}
+
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(env.home, tt.want)
+			}
+			if got := findConfigFile(); got != want {
+				t.Errorf("findConfigFile() = %q, want %q", got, want)
+			}
+
+			if err := Load(""); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if got := viper.ConfigFileUsed(); got != want {
+				t.Errorf("ConfigFileUsed() = %q, want %q", got, want)
+			}
+			if want != "" && GetString("timeout") != "5s" {
+				t.Errorf("timeout = %q, want 5s from %s", GetString("timeout"), tt.want)
+			}
+
+			path, err := Path()
+			if err != nil {
+				t.Fatalf("Path: %v", err)
+			}
+			if wantPath := filepath.Join(env.home, tt.wantPath); path != wantPath {
+				t.Errorf("Path() = %q, want %q", path, wantPath)
+			}
+		})
+	}
+}
+
+func TestValidateProfileOverride(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		flag    string
+		wantErr string
+	}{
+		{name: "none"},
+		{name: "valid flag", flag: "work"},
+		{name: "uppercase env", env: "STAGING"},
+		{name: "dotted flag", flag: "a.b", wantErr: "--profile"},
+		{name: "path in env", env: "../victim", wantErr: "CURSOR_PROFILE"},
+		{name: "separator in flag", flag: "x/y", wantErr: "--profile"},
+		{name: "invalid env, valid flag", env: "../victim", flag: "work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			// current_profile is checked when it is written, not here
+			env.load("current_profile: not.checked\n")
+			if tt.env != "" {
+				t.Setenv("CURSOR_PROFILE", tt.env)
+			}
+			if tt.flag != "" {
+				env.setFlag("profile", tt.flag)
+			}
+
+			err := ValidateProfileOverride()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateProfileOverride() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateProfileOverride() = %v, want an error naming %s", err, tt.wantErr)
+			}
+		})
+	}
+}
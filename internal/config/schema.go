@@ -0,0 +1,412 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/output"
+	"github.com/spf13/viper"
+)
+
+// ValueType is the type of a config setting
+type ValueType string
+
+const (
+	TypeString   ValueType = "string"
+	TypeURL      ValueType = "url"
+	TypeDuration ValueType = "duration"
+	TypeInt      ValueType = "int"
+	TypeBool     ValueType = "bool"
+	TypeEnum     ValueType = "enum"
+
+	// TypeMap settings hold named string values, set one at a time as
+	// "<key>.<name>"
+	TypeMap ValueType = "map"
+)
+
+// Setting describes a config key cursor-cli knows about
+type Setting struct {
+	Key         string
+	Type        ValueType
+	Description string
+
+	// Profile settings are stored in the active profile rather than at
+	// the top level of the config file
+	Profile bool
+
+	// Secret settings are redacted when listed
+	Secret bool
+
+	// Values lists the allowed values of TypeEnum settings
+	Values []string
+
+	// validate performs checks beyond the type, if any
+	validate func(value string) error
+}
+
+// Themes lists the values accepted by the theme setting
+var Themes = []string{"default", "mono"}
+
+// Settings is the schema of the config file
+var Settings = []Setting{
+	{Key: "api_key", Type: TypeString, Profile: true, Secret: true, Description: "API key, stored in the profile's secret backend if it has one"},
+	{Key: "api_key_ref", Type: TypeString, Profile: true, Description: "Reference to the API key in a secret backend (<backend>:<ref>)", validate: validateSecretRef},
+	{Key: "credential_helper", Type: TypeString, Profile: true, Description: "Command storing the API key for the helper backend"},
+	{Key: "base_url", Type: TypeURL, Profile: true, Description: "Base URL of the Cursor API"},
+	{Key: "repository", Type: TypeURL, Profile: true, Description: "Default repository for launch"},
+	{Key: "ref", Type: TypeString, Profile: true, Description: "Default git ref for launch"},
+	{Key: "model", Type: TypeString, Profile: true, Description: "Default model for launch"},
+	{Key: "auto_pr", Type: TypeBool, Profile: true, Description: "Open a pull request when launched agents finish"},
+	{Key: "current_profile", Type: TypeString, Description: "Profile used when --profile is not given", validate: ValidateProfileName},
+	{Key: "timeout", Type: TypeDuration, Description: "Timeout for each API request"},
+	{Key: "proxy", Type: TypeURL, Description: "HTTP, HTTPS or SOCKS5 proxy for API requests"},
+	{Key: "ca_file", Type: TypeString, Description: "PEM file with additional trusted CA certificates"},
+	{Key: "insecure_skip_verify", Type: TypeBool, Description: "Skip TLS certificate verification (testing only)"},
+	{Key: "headers", Type: TypeMap, Description: "Extra headers sent with every request (headers.<name>)"},
+	{Key: "offline", Type: TypeBool, Description: "Read agents and conversations from the local cache instead of the API"},
+	{Key: "refresh_interval", Type: TypeDuration, Description: "How often the TUI refreshes the agent list"},
+	{Key: "output", Type: TypeString, Description: "Default output format", validate: validateOutput},
+	{Key: "theme", Type: TypeEnum, Values: Themes, Description: "TUI color theme"},
+	{Key: "retry.max_attempts", Type: TypeInt, Description: "Total attempts for retryable requests"},
+	{Key: "retry.initial_backoff", Type: TypeDuration, Description: "Delay before the first retry"},
+	{Key: "retry.max_backoff", Type: TypeDuration, Description: "Upper bound for the delay between retries"},
+}
+
+// LookupSetting returns the schema entry of key
+func LookupSetting(key string) (Setting, error) {
+	key = strings.ToLower(key)
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, nil
+		}
+		if setting.Type == TypeMap && strings.HasPrefix(key, setting.Key+".") {
+			return Setting{
+				Key:         key,
+				Type:        TypeString,
+				Description: setting.Description,
+				Profile:     setting.Profile,
+				Secret:      setting.Secret,
+			}, nil
+		}
+	}
+
+	keys := make([]string, len(Settings))
+	for i, setting := range Settings {
+		keys[i] = setting.Key
+	}
+	sort.Strings(keys)
+	return Setting{}, fmt.Errorf("unknown config key %q (known keys: %s)", key, strings.Join(keys, ", "))
+}
+
+// Parse converts a value given on the command line to the type of the
+// setting, as it should be written to the config file
+func (s Setting) Parse(value string) (interface{}, error) {
+	var parsed interface{} = value
+
+	switch s.Type {
+	case TypeMap:
+		return nil, fmt.Errorf("%s holds several values; set them one at a time with %s.<name>", s.Key, s.Key)
+	case TypeURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid value for %s: expected an absolute URL, got %q", s.Key, value)
+		}
+	case TypeDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid value for %s: expected a positive duration such as 30s or 2m, got %q", s.Key, value)
+		}
+		parsed = d.String()
+	case TypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid value for %s: expected a positive integer, got %q", s.Key, value)
+		}
+		parsed = n
+	case TypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: expected true or false, got %q", s.Key, value)
+		}
+		parsed = b
+	case TypeEnum:
+		value = strings.ToLower(value)
+		valid := false
+		for _, allowed := range s.Values {
+			if value == allowed {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid value for %s: expected one of %s, got %q", s.Key, strings.Join(s.Values, ", "), value)
+		}
+		parsed = value
+	}
+
+	if s.validate != nil {
+		if err := s.validate(value); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", s.Key, err)
+		}
+	}
+	return parsed, nil
+}
+
+// path returns where the setting lives in the config file for profile
+func (s Setting) path(profile string) []string {
+	keys := strings.Split(s.Key, ".")
+	if s.Profile {
+		return append([]string{"profiles", profile}, keys...)
+	}
+	return keys
+}
+
+// viperKey returns the viper key of the setting for the active profile
+func (s Setting) viperKey() string {
+	return strings.Join(s.path(ActiveProfile()), ".")
+}
+
+// Get returns the effective value of key and whether it is set. For
+// api_key this is the key resolved from the flag, environment or profile.
+func Get(key string) (interface{}, bool, error) {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if setting.Key == "api_key" {
+		apiKey, err := GetAPIKey()
+		if err != nil {
+			return nil, false, err
+		}
+		return apiKey, true, nil
+	}
+
+	r, ok := Resolve(setting.Key)
+	return r.Value, ok, nil
+}
+
+// Set validates value against the schema and writes it to the config file.
+// Profile settings are written to the active profile. Setting api_key
+// stores it in the profile's secret backend, if it has one.
+func Set(key, value string) error {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := setting.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	if setting.Key == "api_key" {
+		backend := BackendPlain
+		if ref := ProfileString("api_key_ref"); ref != "" {
+			backend, _, _ = strings.Cut(ref, ":")
+		}
+		_, err := SaveAPIKey(value, backend)
+		return err
+	}
+
+	profile := ActiveProfile()
+	viper.Set(setting.viperKey(), parsed)
+	return update(func(doc map[string]interface{}) {
+		setPath(doc, setting.path(profile), parsed)
+		if setting.Profile {
+			setCurrentProfile(doc, profile)
+		}
+	})
+}
+
+// Unset removes key from the config file. Unsetting api_key also removes
+// the key from the profile's secret backend.
+func Unset(key string) error {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+
+	profile := ActiveProfile()
+	paths := [][]string{setting.path(profile)}
+
+	if setting.Key == "api_key" {
+		if ref := ProfileString("api_key_ref"); ref != "" {
+			name, secretRef, _ := strings.Cut(ref, ":")
+			if backend, err := Backend(name); err == nil {
+				if err := backend.Delete(secretRef); err != nil {
+					return fmt.Errorf("error removing API key from %s: %w", name, err)
+				}
+			}
+		}
+		paths = append(paths, []string{"profiles", profile, "api_key_ref"})
+		viper.Set(profileKey(profile, "api_key_ref"), nil)
+	}
+
+	viper.Set(setting.viperKey(), nil)
+	return update(func(doc map[string]interface{}) {
+		for _, path := range paths {
+			deletePath(doc, path)
+		}
+	})
+}
+
+// Entry is a setting as shown by 'config list'
+type Entry struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Profile string `json:"profile,omitempty"`
+}
+
+// List returns the known settings present in the config file, for the
+// active profile, with secrets redacted
+func List() ([]Entry, error) {
+	doc, err := load()
+	if err != nil {
+		return nil, err
+	}
+	profile := ActiveProfile()
+
+	var entries []Entry
+	for _, setting := range Settings {
+		value, ok := lookupPath(doc, setting.path(profile))
+		if !ok {
+			continue
+		}
+
+		values := map[string]interface{}{setting.Key: value}
+		if setting.Type == TypeMap {
+			values, _ = value.(map[string]interface{})
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			entry := Entry{Key: key, Value: fmt.Sprint(values[key])}
+			if setting.Type == TypeMap {
+				entry.Key = setting.Key + "." + key
+			}
+			if setting.Secret {
+				entry.Value = Redact(entry.Value)
+			}
+			if setting.Profile {
+				entry.Profile = profile
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Redact hides all but the first and last few characters of a secret
+func Redact(secret string) string {
+	if len(secret) <= 12 {
+		return "****"
+	}
+	return secret[:4] + "****" + secret[len(secret)-4:]
+}
+
+// ValidateFile checks the known settings of every profile in the config
+// file against the schema
+func ValidateFile() []error {
+	doc, err := load()
+	if err != nil {
+		return []error{err}
+	}
+
+	profiles := []string{""}
+	if all, ok := doc["profiles"].(map[string]interface{}); ok {
+		for name := range all {
+			profiles = append(profiles, name)
+		}
+	}
+	sort.Strings(profiles)
+
+	var errs []error
+	for _, setting := range Settings {
+		for _, profile := range profiles {
+			if setting.Profile != (profile != "") {
+				continue
+			}
+
+			value, ok := lookupPath(doc, setting.path(profile))
+			if !ok {
+				continue
+			}
+
+			var err error
+			if setting.Type == TypeMap {
+				if _, ok := value.(map[string]interface{}); !ok {
+					err = fmt.Errorf("invalid value for %s: expected a mapping of names to values", setting.Key)
+				}
+			} else {
+				_, err = setting.Parse(fmt.Sprint(value))
+			}
+			if err != nil {
+				if profile != "" {
+					err = fmt.Errorf("profile %q: %w", profile, err)
+				}
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// lookupPath returns a nested value from doc
+func lookupPath(doc map[string]interface{}, path []string) (interface{}, bool) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := doc[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		doc = next
+	}
+	value, ok := doc[path[len(path)-1]]
+	return value, ok
+}
+
+// EnsureFile creates an empty config file, readable only by the user, if
+// none exists yet, and returns its path
+func EnsureFile() (string, error) {
+	path, err := Path()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte("# cursor-cli configuration, see 'cursor-cli config list'\n"), 0o600)
+}
+
+func validateOutput(value string) error {
+	_, err := output.Parse(value)
+	return err
+}
+
+func validateSecretRef(value string) error {
+	name, ref, ok := strings.Cut(value, ":")
+	if !ok || ref == "" {
+		return fmt.Errorf("expected <backend>:<ref>, got %q", value)
+	}
+	switch name {
+	case BackendKeyring, BackendFile, BackendHelper:
+		return nil
+	default:
+		return fmt.Errorf("unknown secret backend %q", name)
+	}
+}
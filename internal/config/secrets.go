@@ -0,0 +1,362 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// Names of the places an API key can be stored
+const (
+	BackendAuto    = "auto"    // keyring when available, plain otherwise
+	BackendPlain   = "plain"   // api_key in the config file
+	BackendKeyring = "keyring" // OS keyring (Secret Service or macOS Keychain)
+	BackendFile    = "file"    // age passphrase-encrypted file
+	BackendHelper  = "helper"  // external credential_helper command
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of the
+// encrypted file backend, for non-interactive use
+const PassphraseEnv = "CURSOR_CLI_PASSPHRASE"
+
+// Interactive controls whether secret backends may prompt on the terminal,
+// e.g. for the passphrase of the encrypted file backend
+var Interactive = true
+
+// keyringService is the service name API keys are filed under in the keyring
+const keyringService = "cursor-cli"
+
+// SecretBackend stores API keys outside the config file. The config file
+// then only holds a reference of the form "<backend>:<ref>" in api_key_ref.
+type SecretBackend interface {
+	// Name identifies the backend in api_key_ref values
+	Name() string
+
+	// Available reports whether the backend can be used on this system
+	Available() bool
+
+	Get(ref string) (string, error)
+	Set(ref, secret string) error
+	Delete(ref string) error
+}
+
+// Backend returns the secret backend with the given name
+func Backend(name string) (SecretBackend, error) {
+	switch name {
+	case BackendKeyring:
+		return keyringBackend{}, nil
+	case BackendFile:
+		return fileBackend{}, nil
+	case BackendHelper:
+		command := ProfileString("credential_helper")
+		if command == "" {
+			command = viper.GetString("credential_helper")
+		}
+		if command == "" {
+			return nil, errors.New("no credential_helper configured")
+		}
+		return helperBackend{command: command}, nil
+	default:
+		return nil, fmt.Errorf("unknown secret backend %q (supported: %s, %s, %s, %s)", name, BackendPlain, BackendKeyring, BackendFile, BackendHelper)
+	}
+}
+
+// resolveSecretRef reads the secret behind an api_key_ref value
+func resolveSecretRef(value string) (string, error) {
+	name, ref, ok := strings.Cut(value, ":")
+	if !ok || ref == "" {
+		return "", fmt.Errorf("invalid api_key_ref %q, expected <backend>:<ref>", value)
+	}
+
+	backend, err := Backend(name)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := backend.Get(ref)
+	if err != nil {
+		return "", fmt.Errorf("error reading API key from %s: %w", name, err)
+	}
+	return secret, nil
+}
+
+// keyringBackend stores secrets in the Secret Service over D-Bus (through
+// libsecret's secret-tool) on Linux and in the Keychain on macOS
+type keyringBackend struct{}
+
+func (keyringBackend) Name() string { return BackendKeyring }
+
+func (keyringBackend) Available() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "windows":
+		return false
+	default:
+		_, err := exec.LookPath("secret-tool")
+		return err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+	}
+}
+
+func (b keyringBackend) Get(ref string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", ref, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "profile", ref)
+	}
+
+	out, err := runSecretCommand(cmd, "")
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(out)
+	if secret == "" {
+		return "", fmt.Errorf("no API key found in keyring for %q", ref)
+	}
+	return secret, nil
+}
+
+func (b keyringBackend) Set(ref, secret string) error {
+	if runtime.GOOS == "darwin" {
+		// The command is fed to "security -i" on stdin, hex-encoded with -X,
+		// so the key never shows up in the process list
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+			quoteSecurityArg(keyringService), quoteSecurityArg(ref), hex.EncodeToString([]byte(secret)))
+		if len(command) > maxSecurityCommand {
+			return errors.New("API key is too long for the macOS Keychain")
+		}
+		_, err := runSecretCommand(exec.Command("security", "-i"), command)
+		return err
+	}
+
+	cmd := exec.Command("secret-tool", "store", "--label", fmt.Sprintf("cursor-cli API key (%s)", ref), "service", keyringService, "profile", ref)
+	_, err := runSecretCommand(cmd, secret)
+	return err
+}
+
+func (b keyringBackend) Delete(ref string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", ref)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", keyringService, "profile", ref)
+	}
+	_, err := runSecretCommand(cmd, "")
+
+	// security exits with 44 when there is no such item
+	var exitErr *exec.ExitError
+	if runtime.GOOS == "darwin" && errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+		return nil
+	}
+	return err
+}
+
+// maxSecurityCommand is the longest line "security -i" accepts
+const maxSecurityCommand = 4096
+
+// quoteSecurityArg quotes s for the command line parser of "security -i",
+// which splits arguments like a shell
+func quoteSecurityArg(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fileBackend stores each secret in an age file encrypted with a passphrase
+type fileBackend struct{}
+
+func (fileBackend) Name() string { return BackendFile }
+
+func (fileBackend) Available() bool { return true }
+
+func (b fileBackend) Get(ref string) (string, error) {
+	path, err := secretFilePath(ref)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	passphrase, err := readPassphrase(false)
+	if err != nil {
+		return "", err
+	}
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(data), identity)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting %s (wrong passphrase?): %w", path, err)
+	}
+	secret, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+func (b fileBackend) Set(ref, secret string) error {
+	path, err := secretFilePath(ref)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := readPassphrase(true)
+	if err != nil {
+		return err
+	}
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, secret); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+func (b fileBackend) Delete(ref string) error {
+	path, err := secretFilePath(ref)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// secretFilePath returns where the encrypted secret for ref is kept
+func secretFilePath(ref string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "secrets", ref+".age"), nil
+}
+
+// readPassphrase returns the passphrase from PassphraseEnv, or prompts for
+// it on the terminal. When confirm is set the user has to type it twice.
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !Interactive || !term.IsTerminal(fd) {
+		return "", fmt.Errorf("no terminal to ask for the passphrase; set %s", PassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, "Passphrase for the encrypted API key: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) == 0 {
+		return "", errors.New("passphrase cannot be empty")
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+		again, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		if string(again) != string(passphrase) {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+
+	return string(passphrase), nil
+}
+
+// helperBackend delegates storage to an external command, like git's
+// credential helpers. The command is run through the shell with "get",
+// "store" or "erase" appended, and receives "profile=<ref>" (plus
+// "secret=<key>" for store) on stdin. For get it prints the key on stdout.
+type helperBackend struct {
+	command string
+}
+
+func (helperBackend) Name() string { return BackendHelper }
+
+func (b helperBackend) Available() bool { return b.command != "" }
+
+func (b helperBackend) Get(ref string) (string, error) {
+	out, err := b.run("get", fmt.Sprintf("profile=%s\n", ref))
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(out)
+	if secret == "" {
+		return "", fmt.Errorf("credential helper returned no API key for %q", ref)
+	}
+	return secret, nil
+}
+
+func (b helperBackend) Set(ref, secret string) error {
+	_, err := b.run("store", fmt.Sprintf("profile=%s\nsecret=%s\n", ref, secret))
+	return err
+}
+
+func (b helperBackend) Delete(ref string) error {
+	_, err := b.run("erase", fmt.Sprintf("profile=%s\n", ref))
+	return err
+}
+
+func (b helperBackend) run(action, input string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", b.command+" "+action)
+	} else {
+		cmd = exec.Command("sh", "-c", b.command+" "+action)
+	}
+	return runSecretCommand(cmd, input)
+}
+
+// runSecretCommand runs cmd with input on stdin and returns its stdout,
+// including stderr in the error when it fails
+func runSecretCommand(cmd *exec.Cmd, input string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", filepath.Base(cmd.Path), err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", filepath.Base(cmd.Path), err)
+	}
+	return stdout.String(), nil
+}
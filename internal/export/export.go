@@ -0,0 +1,229 @@
+// Package export renders an agent's conversation as a standalone document
+// in Markdown, HTML, JSON or plain text, for pasting into design docs and
+// postmortems.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+// Format identifies the kind of document to export
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+	FormatText     Format = "txt"
+)
+
+// Formats lists the values accepted by the --export flag
+var Formats = []string{
+	string(FormatMarkdown),
+	string(FormatHTML),
+	string(FormatJSON),
+	string(FormatText),
+}
+
+// ParseFormat parses an --export value such as "md" or "markdown"
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	case "txt", "text":
+		return FormatText, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (supported: %s)", value, strings.Join(Formats, ", "))
+	}
+}
+
+// FormatForFile guesses the format from the extension of a file name
+func FormatForFile(name string) (Format, bool) {
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+	if ext == "" {
+		return "", false
+	}
+	format, err := ParseFormat(ext)
+	return format, err == nil
+}
+
+// Document is an exported conversation with the agent it belongs to
+type Document struct {
+	Agent        client.Agent                `json:"agent"`
+	Conversation client.ConversationResponse `json:"conversation"`
+	ExportedAt   time.Time                   `json:"exportedAt"`
+}
+
+// Write renders doc to w in the given format
+func Write(w io.Writer, format Format, doc Document) error {
+	switch format {
+	case FormatMarkdown:
+		return writeMarkdown(w, doc)
+	case FormatHTML:
+		return writeHTML(w, doc)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(doc)
+	case FormatText:
+		return writeText(w, doc)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// field is a labelled piece of agent metadata
+type field struct {
+	Label string
+	Value string
+	Link  bool
+}
+
+// metadata returns the agent metadata shown at the top of a document,
+// leaving out fields the agent doesn't have
+func (doc Document) metadata() []field {
+	agent := doc.Agent
+	candidates := []field{
+		{Label: "Agent", Value: agent.ID},
+		{Label: "Status", Value: agent.Status},
+		{Label: "Repository", Value: agent.Source.Repository, Link: isURL(agent.Source.Repository)},
+		{Label: "Ref", Value: agent.Source.Ref},
+		{Label: "Branch", Value: agent.Target.BranchName},
+		{Label: "Pull request", Value: agent.Target.PrURL, Link: true},
+		{Label: "Agent URL", Value: agent.Target.URL, Link: true},
+	}
+	if !agent.CreatedAt.IsZero() {
+		candidates = append(candidates, field{Label: "Created", Value: agent.CreatedAt.UTC().Format(time.RFC3339)})
+	}
+	if !doc.ExportedAt.IsZero() {
+		candidates = append(candidates, field{Label: "Exported", Value: doc.ExportedAt.UTC().Format(time.RFC3339)})
+	}
+
+	var fields []field
+	for _, f := range candidates {
+		if f.Value != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// title returns the title of the document
+func (doc Document) title() string {
+	name := doc.Agent.Name
+	if name == "" {
+		name = doc.Agent.ID
+	}
+	if name == "" {
+		name = doc.Conversation.ID
+	}
+	return "Conversation: " + name
+}
+
+// messageRole names the author of a message by its type
+func messageRole(messageType string) string {
+	switch messageType {
+	case "user_message":
+		return "User"
+	case "agent_message":
+		return "Agent"
+	case "system_message":
+		return "System"
+	case "":
+		return "Message"
+	default:
+		return messageType
+	}
+}
+
+// isURL reports whether s looks like an absolute http(s) URL
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// block is a run of prose or a fenced code block within a message
+type block struct {
+	Code bool
+	Lang string
+	Text string
+
+	// Fence is the fence that opened a code block and Closed whether a
+	// matching fence ended it before the message did
+	Fence  string
+	Closed bool
+}
+
+// splitBlocks splits message text into prose and fenced code blocks (```
+// or ~~~), so code can be rendered verbatim
+func splitBlocks(text string) []block {
+	var blocks []block
+	var current *block
+	var lines []string
+
+	flush := func() {
+		if current != nil && (current.Code || len(lines) > 0) {
+			current.Text = strings.Join(lines, "\n")
+			blocks = append(blocks, *current)
+		}
+		current, lines = nil, nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if current != nil && current.Code {
+			if isClosingFence(trimmed, current.Fence) {
+				current.Closed = true
+				flush()
+				continue
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		if fence := openingFence(trimmed); fence != "" {
+			flush()
+			current = &block{Code: true, Fence: fence, Lang: strings.TrimSpace(trimmed[len(fence):])}
+			continue
+		}
+
+		if current == nil {
+			current = &block{}
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return blocks
+}
+
+// openingFence returns the fence a line opens a code block with, if any
+func openingFence(line string) string {
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(line) && line[n] == c {
+			n++
+		}
+		if n >= 3 && !(c == '`' && strings.Contains(line[n:], "`")) {
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// isClosingFence reports whether line closes a code block opened with fence
+func isClosingFence(line, fence string) bool {
+	return len(line) >= len(fence) && strings.Trim(line, fence[:1]) == ""
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+// testDocument is a conversation with markup to escape and code fences to
+// keep
+var testDocument = Document{
+	Agent: client.Agent{ID: "bc_abc123", Name: "Fix <script>alert(1)</script>", Status: client.StatusCompleted},
+	Conversation: client.ConversationResponse{ID: "conv_1", Messages: []client.Message{
+		{ID: "m1", Type: "user_message", Text: "Run <script>alert(\"x\")</script> & `a<b`"},
+		{ID: "m2", Type: "agent_message", Text: "Done:\n\n```go\nif a < b && c > d {\n\tfmt.Println(\"<b>\")\n}\n```\n\nAnd:\n~~~\nraw ``` inside\n~~~"},
+		{ID: "m3", Type: "agent_message", Text: "Unclosed:\n```sh\necho hi"},
+	}},
+	ExportedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+}
+
+// render writes testDocument in format
+func render(t *testing.T, format Format) string {
+	t.Helper()
+	var b strings.Builder
+	if err := Write(&b, format, testDocument); err != nil {
+		t.Fatalf("Write(%s): %v", format, err)
+	}
+	return b.String()
+}
+
+func TestMarkdownKeepsCodeFences(t *testing.T) {
+	want := "# Conversation: Fix <script>alert(1)</script>\n" +
+		"\n" +
+		"| | |\n" +
+		"|---|---|\n" +
+		"| **Agent** | `bc_abc123` |\n" +
+		"| **Status** | `COMPLETED` |\n" +
+		"| **Exported** | `2024-06-01T12:00:00Z` |\n" +
+		"\n" +
+		"## Messages\n" +
+		"\n" +
+		"### 1. User\n" +
+		"\n" +
+		"Run <script>alert(\"x\")</script> & `a<b`\n" +
+		"\n" +
+		"---\n" +
+		"\n" +
+		"### 2. Agent\n" +
+		"\n" +
+		"Done:\n" +
+		"\n" +
+		"```go\n" +
+		"if a < b && c > d {\n" +
+		"\tfmt.Println(\"<b>\")\n" +
+		"}\n" +
+		"```\n" +
+		"\n" +
+		"And:\n" +
+		"~~~\n" +
+		"raw ``` inside\n" +
+		"~~~\n" +
+		"\n" +
+		"---\n" +
+		"\n" +
+		"### 3. Agent\n" +
+		"\n" +
+		"Unclosed:\n" +
+		"```sh\n" +
+		"echo hi\n" +
+		"```\n"
+
+	if got := render(t, FormatMarkdown); got != want {
+		t.Errorf("Markdown export:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHTMLEscapesMarkupAndKeepsCodeFences(t *testing.T) {
+	got := render(t, FormatHTML)
+
+	if strings.Contains(strings.ToLower(got), "<script") {
+		t.Errorf("HTML export contains an unescaped script element:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "<!DOCTYPE html>\n") {
+		t.Errorf("HTML export does not start with a doctype:\n%s", got)
+	}
+	if want := "<title>Conversation: Fix &lt;script&gt;alert(1)&lt;/script&gt;</title>"; !strings.Contains(got, want) {
+		t.Errorf("HTML export has no escaped title %q:\n%s", want, got)
+	}
+
+	// The styles are left out of the comparison
+	body := got[strings.Index(got, "<body>"):]
+	want := "<body>\n" +
+		"<h1>Conversation: Fix &lt;script&gt;alert(1)&lt;/script&gt;</h1>\n" +
+		"<table class=\"meta\">\n" +
+		"<tr><th>Agent</th><td>bc_abc123</td></tr>\n" +
+		"<tr><th>Status</th><td>COMPLETED</td></tr>\n" +
+		"<tr><th>Exported</th><td>2024-06-01T12:00:00Z</td></tr>\n" +
+		"</table>\n" +
+		"<h2>Messages</h2>\n" +
+		"<section class=\"message user\">\n" +
+		"<header><span class=\"role\">1. User</span><span class=\"id\">m1</span></header>\n" +
+		"<p>Run &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; <code>a&lt;b</code></p>\n" +
+		"\n" +
+		"</section>\n" +
+		"<section class=\"message agent\">\n" +
+		"<header><span class=\"role\">2. Agent</span><span class=\"id\">m2</span></header>\n" +
+		"<p>Done:</p>\n" +
+		"<pre><code class=\"language-go\">if a &lt; b &amp;&amp; c &gt; d {\n" +
+		"\tfmt.Println(&#34;&lt;b&gt;&#34;)\n" +
+		"}</code></pre>\n" +
+		"<p>And:</p>\n" +
+		"<pre><code>raw ``` inside</code></pre>\n" +
+		"\n" +
+		"</section>\n" +
+		"<section class=\"message agent\">\n" +
+		"<header><span class=\"role\">3. Agent</span><span class=\"id\">m3</span></header>\n" +
+		"<p>Unclosed:</p>\n" +
+		"<pre><code class=\"language-sh\">echo hi</code></pre>\n" +
+		"\n" +
+		"</section>\n" +
+		"</body>\n" +
+		"</html>\n"
+
+	if body != want {
+		t.Errorf("HTML export body:\n%s\nwant:\n%s", body, want)
+	}
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// htmlMessage is a message as passed to htmlTemplate
+type htmlMessage struct {
+	Number int
+	Role   string
+	Class  string
+	ID     string
+	Body   template.HTML
+}
+
+// writeHTML renders doc as a self-contained HTML page: the styles are
+// inlined and nothing is loaded from elsewhere
+func writeHTML(w io.Writer, doc Document) error {
+	data := struct {
+		Title    string
+		Fields   []field
+		Summary  template.HTML
+		Messages []htmlMessage
+	}{
+		Title:   doc.title(),
+		Fields:  doc.metadata(),
+		Summary: renderHTMLText(strings.TrimSpace(doc.Agent.Summary)),
+	}
+
+	for i, message := range doc.Conversation.Messages {
+		class := strings.TrimSuffix(message.Type, "_message")
+		if class != "user" && class != "agent" && class != "system" {
+			class = "other"
+		}
+		data.Messages = append(data.Messages, htmlMessage{
+			Number: i + 1,
+			Role:   messageRole(message.Type),
+			Class:  class,
+			ID:     message.ID,
+			Body:   renderHTMLText(message.Text),
+		})
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
+
+// renderHTMLText renders message text: fenced code blocks verbatim,
+// prose as paragraphs with line breaks and `inline code` kept
+func renderHTMLText(text string) template.HTML {
+	var b strings.Builder
+	for _, blk := range splitBlocks(text) {
+		if blk.Code {
+			b.WriteString("<pre><code")
+			if blk.Lang != "" {
+				b.WriteString(` class="language-` + html.EscapeString(strings.Fields(blk.Lang)[0]) + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(blk.Text) + "</code></pre>\n")
+			continue
+		}
+
+		for _, paragraph := range strings.Split(blk.Text, "\n\n") {
+			paragraph = strings.Trim(paragraph, "\n")
+			if strings.TrimSpace(paragraph) == "" {
+				continue
+			}
+			var lines []string
+			for _, line := range strings.Split(paragraph, "\n") {
+				lines = append(lines, renderInlineCode(line))
+			}
+			b.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+		}
+	}
+	return template.HTML(b.String())
+}
+
+// renderInlineCode escapes a line of prose, turning `spans` into code
+// elements when the backticks are balanced
+func renderInlineCode(line string) string {
+	parts := strings.Split(line, "`")
+	if len(parts)%2 == 0 {
+		return html.EscapeString(line)
+	}
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString("<code>" + html.EscapeString(part) + "</code>")
+		} else {
+			b.WriteString(html.EscapeString(part))
+		}
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("conversation").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+:root {
+  --fg: #1f2328; --muted: #59636e; --bg: #ffffff; --panel: #f6f8fa; --border: #d1d9e0;
+  --user: #0969da; --agent: #1a7f37; --system: #9a6700; --other: #59636e;
+}
+@media (prefers-color-scheme: dark) {
+  :root {
+    --fg: #e6edf3; --muted: #9198a1; --bg: #0d1117; --panel: #151b23; --border: #3d444d;
+    --user: #4493f8; --agent: #3fb950; --system: #d29922; --other: #9198a1;
+  }
+}
+* { box-sizing: border-box; }
+body { margin: 0 auto; max-width: 56rem; padding: 2rem 1.25rem; color: var(--fg); background: var(--bg);
+  font: 15px/1.55 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+h1 { font-size: 1.6rem; margin: 0 0 1rem; }
+h2 { font-size: 1.2rem; margin: 2rem 0 .75rem; padding-bottom: .3rem; border-bottom: 1px solid var(--border); }
+table.meta { border-collapse: collapse; }
+table.meta th { text-align: left; padding: .2rem 1rem .2rem 0; color: var(--muted); font-weight: 600; white-space: nowrap; vertical-align: top; }
+table.meta td { padding: .2rem 0; word-break: break-all; }
+a { color: var(--user); }
+.message { margin: 1rem 0; padding: .75rem 1rem; border: 1px solid var(--border); border-left: 4px solid var(--other); border-radius: 6px; background: var(--panel); }
+.message.user { border-left-color: var(--user); }
+.message.agent { border-left-color: var(--agent); }
+.message.system { border-left-color: var(--system); }
+.message header { display: flex; justify-content: space-between; gap: 1rem; margin-bottom: .5rem; font-weight: 600; }
+.message header .id { color: var(--muted); font-weight: normal; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: .8rem; }
+.message.user header .role { color: var(--user); }
+.message.agent header .role { color: var(--agent); }
+.message.system header .role { color: var(--system); }
+p { margin: .5rem 0; overflow-wrap: anywhere; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: .85em; }
+p code { padding: .1em .35em; border-radius: 4px; background: var(--bg); border: 1px solid var(--border); }
+pre { margin: .5rem 0; padding: .75rem 1rem; overflow-x: auto; border-radius: 6px; background: var(--bg); border: 1px solid var(--border); }
+.empty { color: var(--muted); font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .Fields}}
+<table class="meta">
+{{- range .Fields}}
+<tr><th>{{.Label}}</th><td>{{if .Link}}<a href="{{.Value}}">{{.Value}}</a>{{else}}{{.Value}}{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Summary}}
+<h2>Summary</h2>
+{{.Summary}}
+{{- end}}
+<h2>Messages</h2>
+{{- range .Messages}}
+<section class="message {{.Class}}">
+<header><span class="role">{{.Number}}. {{.Role}}</span>{{if .ID}}<span class="id">{{.ID}}</span>{{end}}</header>
+{{.Body}}
+</section>
+{{- else}}
+<p class="empty">No messages in this conversation.</p>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeMarkdown renders doc as Markdown. Message text is kept verbatim, as
+// it usually is Markdown already.
+func writeMarkdown(w io.Writer, doc Document) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", doc.title())
+
+	if fields := doc.metadata(); len(fields) > 0 {
+		b.WriteString("| | |\n|---|---|\n")
+		for _, f := range fields {
+			value := strings.ReplaceAll(f.Value, "|", `\|`)
+			if !f.Link {
+				value = "`" + value + "`"
+			}
+			fmt.Fprintf(&b, "| **%s** | %s |\n", f.Label, value)
+		}
+		b.WriteString("\n")
+	}
+
+	if summary := strings.TrimSpace(doc.Agent.Summary); summary != "" {
+		fmt.Fprintf(&b, "## Summary\n\n%s\n\n", summary)
+	}
+
+	b.WriteString("## Messages\n\n")
+	if len(doc.Conversation.Messages) == 0 {
+		b.WriteString("_No messages in this conversation._\n")
+	}
+	for i, message := range doc.Conversation.Messages {
+		if i > 0 {
+			b.WriteString("\n---\n\n")
+		}
+		fmt.Fprintf(&b, "### %d. %s\n\n", i+1, messageRole(message.Type))
+
+		text := strings.TrimRight(message.Text, "\n")
+		b.WriteString(text + "\n")
+
+		// Close a code block the message left open, so it doesn't swallow
+		// the rest of the document
+		if blocks := splitBlocks(text); len(blocks) > 0 {
+			if last := blocks[len(blocks)-1]; last.Code && !last.Closed {
+				b.WriteString(last.Fence + "\n")
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
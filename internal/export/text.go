@@ -0,0 +1,46 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeText renders doc as plain text
+func writeText(w io.Writer, doc Document) error {
+	var b strings.Builder
+
+	title := doc.title()
+	fmt.Fprintf(&b, "%s\n%s\n\n", title, strings.Repeat("=", len([]rune(title))))
+
+	fields := doc.metadata()
+	width := 0
+	for _, f := range fields {
+		width = max(width, len(f.Label)+1)
+	}
+	for _, f := range fields {
+		fmt.Fprintf(&b, "%-*s %s\n", width, f.Label+":", f.Value)
+	}
+
+	if summary := strings.TrimSpace(doc.Agent.Summary); summary != "" {
+		b.WriteString("\nSummary:\n")
+		for _, line := range strings.Split(summary, "\n") {
+			b.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+		}
+	}
+
+	if len(doc.Conversation.Messages) == 0 {
+		b.WriteString("\nNo messages in this conversation.\n")
+	}
+	for i, message := range doc.Conversation.Messages {
+		header := fmt.Sprintf("[%d] %s", i+1, messageRole(message.Type))
+		if message.ID != "" {
+			header += fmt.Sprintf(" (%s)", message.ID)
+		}
+		fmt.Fprintf(&b, "\n%s\n%s\n", header, strings.Repeat("-", len([]rune(header))))
+		b.WriteString(strings.TrimRight(message.Text, "\n") + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
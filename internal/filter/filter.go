@@ -0,0 +1,250 @@
+// Package filter selects agents by status, repository, name, branch and
+// creation time. It is shared by the list command and the TUI dashboard.
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+// Options are the filter criteria as given on the command line. Empty
+// fields don't restrict the agents.
+type Options struct {
+	// Statuses keeps agents in any of these statuses, case-insensitive
+	Statuses []string
+
+	// IncludeExpired keeps expired agents when Statuses is empty; they are
+	// hidden otherwise
+	IncludeExpired bool
+
+	// Repo is a glob matched against the repository URL, its host and
+	// path ("github.com/org/repo"), "org/repo" or "repo"
+	Repo string
+
+	// Name is a case-insensitive substring of the agent name, or a
+	// regular expression when enclosed in slashes ("/^fix-.*/")
+	Name string
+
+	// Branch is a glob matched against the branch the agent works on
+	Branch string
+
+	// Since and Until bound the creation time, as a duration before now
+	// ("2h", "7d", "2w") or a date ("2024-06-01", "2024-06-01T15:04",
+	// RFC 3339)
+	Since string
+	Until string
+}
+
+// Filter decides which agents to show. The zero Filter matches every
+// agent.
+type Filter struct {
+	statuses    map[string]bool
+	hideExpired bool
+	repo        string
+	name        *regexp.Regexp
+	branch      string
+	since       time.Time
+	until       time.Time
+}
+
+// New validates opts and returns the filter they describe, with relative
+// times taken from now
+func New(opts Options, now time.Time) (Filter, error) {
+	f := Filter{hideExpired: !opts.IncludeExpired}
+
+	for _, status := range opts.Statuses {
+		for _, s := range strings.Split(status, ",") {
+			s = strings.ToUpper(strings.TrimSpace(s))
+			if !isStatus(s) {
+				return Filter{}, fmt.Errorf("unknown status %q, expected one of %s", s, strings.Join(client.Statuses, ", "))
+			}
+			if f.statuses == nil {
+				f.statuses = make(map[string]bool)
+			}
+			f.statuses[s] = true
+		}
+	}
+
+	if opts.Repo != "" {
+		f.repo = strings.ToLower(opts.Repo)
+		if _, err := path.Match(f.repo, ""); err != nil {
+			return Filter{}, fmt.Errorf("invalid repository pattern %q: %w", opts.Repo, err)
+		}
+	}
+
+	if opts.Branch != "" {
+		f.branch = opts.Branch
+		if _, err := path.Match(f.branch, ""); err != nil {
+			return Filter{}, fmt.Errorf("invalid branch pattern %q: %w", opts.Branch, err)
+		}
+	}
+
+	if opts.Name != "" {
+		pattern := "(?i)" + regexp.QuoteMeta(opts.Name)
+		if len(opts.Name) > 2 && strings.HasPrefix(opts.Name, "/") && strings.HasSuffix(opts.Name, "/") {
+			pattern = opts.Name[1 : len(opts.Name)-1]
+		}
+		name, err := regexp.Compile(pattern)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid name pattern %q: %w", opts.Name, err)
+		}
+		f.name = name
+	}
+
+	var err error
+	if opts.Since != "" {
+		if f.since, err = ParseTime(opts.Since, now); err != nil {
+			return Filter{}, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if opts.Until != "" {
+		if f.until, err = ParseTime(opts.Until, now); err != nil {
+			return Filter{}, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if !f.since.IsZero() && !f.until.IsZero() && !f.since.Before(f.until) {
+		return Filter{}, fmt.Errorf("--since %s is not before --until %s", opts.Since, opts.Until)
+	}
+
+	return f, nil
+}
+
+// WithExpired returns a copy of the filter that shows or hides expired
+// agents when no statuses are selected
+func (f Filter) WithExpired(show bool) Filter {
+	f.hideExpired = !show
+	return f
+}
+
+// ShowsExpired reports whether expired agents can match
+func (f Filter) ShowsExpired() bool {
+	if len(f.statuses) > 0 {
+		return f.statuses[client.StatusExpired]
+	}
+	return !f.hideExpired
+}
+
+// Narrowed reports whether the filter has criteria beyond hiding expired
+// agents. Such filters may reject whole pages of agents, so callers listing
+// a single page should follow pagination instead.
+func (f Filter) Narrowed() bool {
+	return len(f.statuses) > 0 || f.repo != "" || f.name != nil || f.branch != "" ||
+		!f.since.IsZero() || !f.until.IsZero()
+}
+
+// Match reports whether agent passes every criterion of the filter
+func (f Filter) Match(agent client.Agent) bool {
+	status := strings.ToUpper(agent.Status)
+	if len(f.statuses) > 0 {
+		if !f.statuses[status] {
+			return false
+		}
+	} else if f.hideExpired && status == client.StatusExpired {
+		return false
+	}
+
+	if f.repo != "" && !matchRepo(f.repo, agent.Source.Repository) {
+		return false
+	}
+	if f.name != nil && !f.name.MatchString(agent.Name) {
+		return false
+	}
+	if f.branch != "" {
+		if ok, _ := path.Match(f.branch, agent.Target.BranchName); !ok {
+			return false
+		}
+	}
+
+	if !f.since.IsZero() && agent.CreatedAt.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !agent.CreatedAt.Before(f.until) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the agents that match the filter, keeping their order
+func (f Filter) Apply(agents []client.Agent) []client.Agent {
+	var matched []client.Agent
+	for _, agent := range agents {
+		if f.Match(agent) {
+			matched = append(matched, agent)
+		}
+	}
+	return matched
+}
+
+// matchRepo matches the lower-cased glob pattern against the forms of a
+// repository URL users are likely to type
+func matchRepo(pattern, repository string) bool {
+	repository = strings.ToLower(strings.TrimSuffix(repository, ".git"))
+
+	candidates := []string{repository}
+	hostPath := repository
+	if u, err := url.Parse(repository); err == nil && u.Host != "" {
+		hostPath = u.Host + u.Path
+	}
+	candidates = append(candidates, hostPath)
+
+	segments := strings.Split(strings.Trim(hostPath, "/"), "/")
+	if n := len(segments); n >= 2 {
+		candidates = append(candidates, segments[n-2]+"/"+segments[n-1])
+	}
+	candidates = append(candidates, segments[len(segments)-1])
+
+	for _, candidate := range candidates {
+		if ok, _ := path.Match(pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTime parses a point in time given as a duration before now ("90m",
+// "2h", "7d", "2w") or as a date in the local time zone ("2024-06-01",
+// "2024-06-01T15:04") or RFC 3339
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+			days := count
+			if value[n-1] == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as a duration (2h, 7d) or date (2006-01-02)", value)
+}
+
+// isStatus reports whether status is a known agent status
+func isStatus(status string) bool {
+	for _, s := range client.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
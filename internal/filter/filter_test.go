@@ -0,0 +1,186 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+// now is the fixed reference time relative times are taken from
+var now = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "empty", opts: Options{}},
+		{name: "status", opts: Options{Statuses: []string{"running"}}},
+		{name: "comma-separated statuses", opts: Options{Statuses: []string{"RUNNING, failed"}}},
+		{name: "unknown status", opts: Options{Statuses: []string{"RUNNING,DONE"}}, wantErr: true},
+		{name: "empty status", opts: Options{Statuses: []string{"RUNNING,"}}, wantErr: true},
+		{name: "invalid repository pattern", opts: Options{Repo: "org/[repo"}, wantErr: true},
+		{name: "invalid branch pattern", opts: Options{Branch: "feature/[x"}, wantErr: true},
+		{name: "invalid name regexp", opts: Options{Name: "/fix-(/"}, wantErr: true},
+		{name: "name with regexp characters", opts: Options{Name: "fix-("}},
+		{name: "invalid since", opts: Options{Since: "yesterday"}, wantErr: true},
+		{name: "invalid until", opts: Options{Until: "2024-13-01"}, wantErr: true},
+		{name: "since before until", opts: Options{Since: "7d", Until: "2h"}},
+		{name: "since after until", opts: Options{Since: "2h", Until: "7d"}, wantErr: true},
+		{name: "since equal to until", opts: Options{Since: "2024-06-01", Until: "2024-06-01"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.opts, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New(%+v) error = %v, want error %v", tt.opts, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "2h", want: now.Add(-2 * time.Hour)},
+		{value: " 2h ", want: now.Add(-2 * time.Hour)},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "2w", want: now.AddDate(0, 0, -14)},
+		{value: "0d", want: now},
+		{value: "2024-06-01", want: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-06-01T15:04", want: time.Date(2024, 6, 1, 15, 4, 0, 0, time.UTC)},
+		{value: "2024-06-01 15:04", want: time.Date(2024, 6, 1, 15, 4, 0, 0, time.UTC)},
+		{value: "2024-06-01T15:04:05", want: time.Date(2024, 6, 1, 15, 4, 5, 0, time.UTC)},
+		{value: "2024-06-01T15:04:05+02:00", want: time.Date(2024, 6, 1, 13, 4, 5, 0, time.UTC)},
+		{value: "2024-06-01T15:04:05Z", want: time.Date(2024, 6, 1, 15, 4, 5, 0, time.UTC)},
+		{value: "-2d", wantErr: true},
+		{value: "d", wantErr: true},
+		{value: "2x", wantErr: true},
+		{value: "06/01/2024", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTime(tt.value, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTime(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTime(%q): %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	agent := client.Agent{
+		ID:        "bc_abc123",
+		Name:      "Fix-login bug",
+		Status:    client.StatusRunning,
+		Source:    client.Source{Repository: "https://github.com/Acme/Web-App.git"},
+		Target:    client.Target{BranchName: "cursor/fix-login"},
+		CreatedAt: now.Add(-3 * time.Hour),
+	}
+	expired := agent
+	expired.Status = client.StatusExpired
+
+	tests := []struct {
+		name  string
+		opts  Options
+		agent client.Agent
+		want  bool
+	}{
+		{name: "no criteria", agent: agent, want: true},
+		{name: "status", opts: Options{Statuses: []string{"running"}}, agent: agent, want: true},
+		{name: "other status", opts: Options{Statuses: []string{"FAILED,COMPLETED"}}, agent: agent, want: false},
+		{name: "expired hidden", agent: expired, want: false},
+		{name: "expired included", opts: Options{IncludeExpired: true}, agent: expired, want: true},
+		{name: "expired selected by status", opts: Options{Statuses: []string{"EXPIRED"}}, agent: expired, want: true},
+
+		{name: "repository URL", opts: Options{Repo: "https://github.com/acme/web-app"}, agent: agent, want: true},
+		{name: "repository host and path", opts: Options{Repo: "github.com/acme/web-app"}, agent: agent, want: true},
+		{name: "repository org and name", opts: Options{Repo: "Acme/Web-App"}, agent: agent, want: true},
+		{name: "repository name", opts: Options{Repo: "web-app"}, agent: agent, want: true},
+		{name: "repository glob", opts: Options{Repo: "acme/web-*"}, agent: agent, want: true},
+		{name: "repository glob on host", opts: Options{Repo: "github.com/*/web-app"}, agent: agent, want: true},
+		{name: "other repository", opts: Options{Repo: "acme/api"}, agent: agent, want: false},
+		{name: "repository name prefix", opts: Options{Repo: "web"}, agent: agent, want: false},
+
+		{name: "name substring", opts: Options{Name: "login"}, agent: agent, want: true},
+		{name: "name substring ignores case", opts: Options{Name: "FIX-LOGIN"}, agent: agent, want: true},
+		{name: "name substring is literal", opts: Options{Name: "fix.login"}, agent: agent, want: false},
+		{name: "name regexp", opts: Options{Name: "/^Fix-.*bug$/"}, agent: agent, want: true},
+		{name: "name regexp is case-sensitive", opts: Options{Name: "/^fix-/"}, agent: agent, want: false},
+		{name: "name regexp with flags", opts: Options{Name: "/(?i)^fix-/"}, agent: agent, want: true},
+
+		{name: "branch", opts: Options{Branch: "cursor/fix-login"}, agent: agent, want: true},
+		{name: "branch glob", opts: Options{Branch: "cursor/*"}, agent: agent, want: true},
+		{name: "branch glob stops at slashes", opts: Options{Branch: "*"}, agent: agent, want: false},
+		{name: "other branch", opts: Options{Branch: "main"}, agent: agent, want: false},
+
+		{name: "since", opts: Options{Since: "4h"}, agent: agent, want: true},
+		{name: "created before since", opts: Options{Since: "2h"}, agent: agent, want: false},
+		{name: "until", opts: Options{Until: "2h"}, agent: agent, want: true},
+		{name: "created after until", opts: Options{Until: "4h"}, agent: agent, want: false},
+		{name: "since and until", opts: Options{Since: "1d", Until: "2024-06-15T10:00"}, agent: agent, want: true},
+		{name: "created at until", opts: Options{Until: "3h"}, agent: agent, want: false},
+
+		{name: "every criterion", opts: Options{Statuses: []string{"RUNNING"}, Repo: "web-app", Name: "login", Branch: "cursor/*", Since: "1d"}, agent: agent, want: true},
+		{name: "one criterion fails", opts: Options{Statuses: []string{"RUNNING"}, Repo: "web-app", Name: "signup", Branch: "cursor/*"}, agent: agent, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.opts, now)
+			if err != nil {
+				t.Fatalf("New(%+v): %v", tt.opts, err)
+			}
+			if got := f.Match(tt.agent); got != tt.want {
+				t.Errorf("Match with %+v = %v, want %v", tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNarrowed(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want bool
+	}{
+		{name: "no criteria", want: false},
+		{name: "expired included", opts: Options{IncludeExpired: true}, want: false},
+		{name: "status", opts: Options{Statuses: []string{"RUNNING"}}, want: true},
+		{name: "repository", opts: Options{Repo: "web-app"}, want: true},
+		{name: "name", opts: Options{Name: "fix"}, want: true},
+		{name: "branch", opts: Options{Branch: "main"}, want: true},
+		{name: "since", opts: Options{Since: "2h"}, want: true},
+		{name: "until", opts: Options{Until: "2h"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.opts, now)
+			if err != nil {
+				t.Fatalf("New(%+v): %v", tt.opts, err)
+			}
+			if got := f.Narrowed(); got != tt.want {
+				t.Errorf("Narrowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies how command results are printed
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatNDJSON   Format = "ndjson"
+	FormatCSV      Format = "csv"
+	FormatTemplate Format = "template"
+)
+
+// Formats lists the values accepted by the --output flag
+var Formats = []string{
+	string(FormatText),
+	string(FormatJSON),
+	string(FormatYAML),
+	string(FormatNDJSON),
+	string(FormatCSV),
+	string(FormatTemplate) + "=<go-template>",
+}
+
+// Options describes the selected output format
+type Options struct {
+	Format   Format
+	Template *template.Template
+}
+
+// IsText reports whether the human-readable text output was selected
+func (o Options) IsText() bool {
+	return o.Format == "" || o.Format == FormatText
+}
+
+// Parse parses an --output value such as "json" or "template={{.ID}}"
+func Parse(value string) (Options, error) {
+	name, text, hasTemplate := strings.Cut(value, "=")
+
+	switch Format(strings.ToLower(name)) {
+	case "", FormatText:
+		return Options{Format: FormatText}, nil
+	case FormatJSON:
+		return Options{Format: FormatJSON}, nil
+	case FormatYAML, "yml":
+		return Options{Format: FormatYAML}, nil
+	case FormatNDJSON, "jsonl":
+		return Options{Format: FormatNDJSON}, nil
+	case FormatCSV:
+		return Options{Format: FormatCSV}, nil
+	case FormatTemplate, "go-template":
+		if !hasTemplate || text == "" {
+			return Options{}, fmt.Errorf("template output requires a template, e.g. --output 'template={{.ID}}'")
+		}
+		tmpl, err := template.New("output").Parse(text)
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid output template: %w", err)
+		}
+		return Options{Format: FormatTemplate, Template: tmpl}, nil
+	default:
+		return Options{}, fmt.Errorf("unknown output format %q (supported: %s)", value, strings.Join(Formats, ", "))
+	}
+}
+
+// Table is the tabular form of a result, used by the csv format
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Write renders v in the machine-readable format selected by opts. Slices
+// are written one element per line by the ndjson and template formats;
+// table is only used by the csv format, which omits the header row when
+// table.Header is nil.
+func Write(w io.Writer, opts Options, v interface{}, table Table) error {
+	// Empty results are written as [] rather than null
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.IsNil() {
+		v = []interface{}{}
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+
+	case FormatYAML:
+		return writeYAML(w, v)
+
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, item := range items(v) {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if table.Header != nil {
+			if err := cw.Write(table.Header); err != nil {
+				return err
+			}
+		}
+		if err := cw.WriteAll(table.Rows); err != nil {
+			return err
+		}
+		return cw.Error()
+
+	case FormatTemplate:
+		for _, item := range items(v) {
+			if err := opts.Template.Execute(w, item); err != nil {
+				return fmt.Errorf("error executing output template: %w", err)
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("output format %q cannot be written as data", opts.Format)
+	}
+}
+
+// items returns the elements of v if it is a slice, or v itself otherwise
+func items(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []interface{}{v}
+	}
+
+	result := make([]interface{}, rv.Len())
+	for i := range result {
+		result[i] = rv.Index(i).Interface()
+	}
+	return result
+}
+
+// writeYAML writes v as YAML. The value goes through its JSON encoding first
+// so field names and order match the json output.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// clearStyle resets the JSON flow/quoting style picked up while parsing, so
+// the document is emitted in regular block YAML
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -0,0 +1,53 @@
+package output
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// StripEmoji removes emoji, and the spacing after them, from s. It is meant
+// for the CLI's own decorations when output goes to files and pipes rather
+// than a terminal.
+func StripEmoji(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	trim := false
+	for _, r := range s {
+		switch {
+		case isEmoji(r):
+			trim = true
+		case trim && r == ' ':
+		default:
+			trim = false
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isEmoji reports whether r is a pictographic character or one of the
+// joiners and variation selectors used to compose emoji
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, transport, ...
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Stars, arrows and other symbols
+		return true
+	case r >= 0x23E9 && r <= 0x23FA, r == 0x231A, r == 0x231B: // Clocks, hourglasses and media controls
+		return true
+	case r == 0xFE0F || r == 0x200D: // Variation selector and zero-width joiner
+		return true
+	default:
+		return false
+	}
+}
@@ -36,8 +36,13 @@ func (m *ConversationModel) Update(msg tea.Msg) (ConversationModel, tea.Cmd) {
 		}
 
 	case ConversationMsg:
+		// Keep following the end of the conversation as messages arrive
+		follow := m.viewport.TotalLineCount() > 0 && m.viewport.AtBottom()
 		content := m.renderConversation(&msg.Conversation)
 		m.viewport.SetContent(content)
+		if follow {
+			m.viewport.GotoBottom()
+		}
 	}
 
 	m.viewport, cmd = m.viewport.Update(msg)
@@ -3,12 +3,14 @@ package models
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/filter"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/tui/styles"
 )
 
@@ -16,9 +18,25 @@ import (
 type DashboardModel struct {
 	table          table.Model
 	showAll        bool
+	filter         filter.Filter
 	selectedRow    int
 	filteredAgents []client.Agent
 	allAgents      []client.Agent
+
+	// Pagination: whether the API has more agents, and whether the next
+	// page is being loaded after scrolling past the last row or, when
+	// filling, to fill the table with agents matching a narrowed filter
+	hasMore     bool
+	loadingMore bool
+	filling     bool
+
+	// When the agents shown were fetched, if read from the local cache
+	cachedAt time.Time
+
+	// Destructive action awaiting confirmation
+	pendingAction string
+	pendingAgent  client.Agent
+	notice        string
 }
 
 // NewDashboardModel creates a new dashboard model
@@ -52,20 +70,82 @@ func NewDashboardModel() DashboardModel {
 	return DashboardModel{
 		table:   t,
 		showAll: false,
+		filter:  filter.Filter{}.WithExpired(false),
 	}
 }
 
+// WithFilter returns the dashboard showing only the agents matching f.
+// The 't' key still toggles whether expired agents are shown.
+func (m DashboardModel) WithFilter(f filter.Filter) DashboardModel {
+	m.filter = f.WithExpired(m.showAll)
+	return m
+}
+
 // Update updates the dashboard model
 func (m DashboardModel) Update(msg tea.Msg) (DashboardModel, tea.Cmd) {
-	var cmd tea.Cmd
+	var cmd, fill tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Match the height View renders the table with
+		m.table.SetHeight(msg.Height - 10)
+		fill = m.fillTable()
+
 	case tea.KeyMsg:
+		// Resolve a pending stop/delete confirmation before anything else
+		if m.pendingAction != "" {
+			action, agent := m.pendingAction, m.pendingAgent
+			m.pendingAction = ""
+			if msg.String() != "y" && msg.String() != "Y" {
+				m.notice = ""
+				return m, nil
+			}
+			if action == "stop" {
+				m.notice = fmt.Sprintf("Stopping %s...", agent.ID)
+				return m, func() tea.Msg {
+					return StopAgentMsg{Agent: agent}
+				}
+			}
+			m.notice = fmt.Sprintf("Deleting %s...", agent.ID)
+			return m, func() tea.Msg {
+				return DeleteAgentMsg{Agent: agent}
+			}
+		}
+
 		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("x"))):
+			if agent, ok := m.currentAgent(); ok {
+				if agent.Status != "RUNNING" {
+					m.notice = "Only running agents can be stopped"
+					return m, nil
+				}
+				m.pendingAction = "stop"
+				m.pendingAgent = agent
+				return m, nil
+			}
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("D"))):
+			if agent, ok := m.currentAgent(); ok {
+				m.pendingAction = "delete"
+				m.pendingAgent = agent
+				return m, nil
+			}
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("down", "j"))):
+			atEnd := len(m.filteredAgents) == 0 || m.table.Cursor() >= len(m.filteredAgents)-1
+			if atEnd && m.hasMore && !m.loadingMore {
+				m.loadingMore = true
+				return m, func() tea.Msg {
+					return LoadMoreAgentsMsg{}
+				}
+			}
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("t"))):
 			m.showAll = !m.showAll
+			m.filter = m.filter.WithExpired(m.showAll)
 			// Re-filter the table with current agents
 			m.updateTable(m.allAgents)
+			fill = m.fillTable()
 
 		case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
 			selectedRow := m.table.Cursor()
@@ -79,13 +159,46 @@ func (m DashboardModel) Update(msg tea.Msg) (DashboardModel, tea.Cmd) {
 		}
 
 	case AgentsMsg:
+		m.hasMore = msg.NextCursor != ""
+		m.cachedAt = msg.CachedAt
 		m.updateTable(msg.Agents)
+		if m.loadingMore {
+			if !m.filling {
+				// Continue the scroll that triggered loading the page
+				m.table.MoveDown(1)
+			}
+			m.loadingMore, m.filling = false, false
+		}
+		fill = m.fillTable()
+
+	case ErrorMsg:
+		m.loadingMore, m.filling = false, false
+
+	case AgentStoppedMsg:
+		m.notice = fmt.Sprintf("Stopped %s", msg.AgentID)
+
+	case AgentDeletedMsg:
+		m.notice = fmt.Sprintf("Deleted %s", msg.AgentID)
 	}
 
 	m.table, cmd = m.table.Update(msg)
 	m.selectedRow = m.table.Cursor()
 
-	return m, cmd
+	return m, tea.Batch(cmd, fill)
+}
+
+// fillTable requests the next page of agents while a narrowed filter leaves
+// fewer rows than the table shows, as the matching agents may all be on
+// later pages. It stops once the rows fill the table or every page has
+// been loaded.
+func (m *DashboardModel) fillTable() tea.Cmd {
+	if !m.filter.Narrowed() || !m.hasMore || m.loadingMore || len(m.filteredAgents) >= m.table.Height() {
+		return nil
+	}
+	m.loadingMore, m.filling = true, true
+	return func() tea.Msg {
+		return LoadMoreAgentsMsg{}
+	}
 }
 
 // View renders the dashboard view
@@ -102,24 +215,39 @@ func (m DashboardModel) View(width, height int, agents []client.Agent, selectedA
 		filterText = "● All Agents"
 	}
 
-	activeCount := 0
-	for _, agent := range agents {
-		if agent.Status != "EXPIRED" {
-			activeCount++
-		}
+	if m.filter.Narrowed() {
+		filterText += ", filtered"
 	}
 
 	statusLine := fmt.Sprintf("%s (%d) | Press 't' to toggle | Press '?' for help",
 		filterText,
-		func() int {
-			if m.showAll {
-				return len(agents)
-			}
-			return activeCount
-		}())
+		len(m.filteredAgents))
+
+	if !m.cachedAt.IsZero() {
+		statusLine += " | 📴 Offline: cached " + m.cachedAt.Local().Format("2006-01-02 15:04")
+	}
+
+	switch {
+	case m.loadingMore:
+		statusLine += " | Loading more agents..."
+	case m.hasMore:
+		statusLine += " | More agents: scroll past the end to load"
+	}
 
 	content.WriteString(styles.InfoStyle.Render(statusLine) + "\n")
 
+	// Confirmation prompt or result of the last action
+	switch m.pendingAction {
+	case "stop":
+		content.WriteString(styles.WarningStyle.Render(fmt.Sprintf("Stop agent %s (%s)? y/N", m.pendingAgent.ID, m.pendingAgent.Name)) + "\n")
+	case "delete":
+		content.WriteString(styles.WarningStyle.Render(fmt.Sprintf("Permanently delete agent %s (%s)? y/N", m.pendingAgent.ID, m.pendingAgent.Name)) + "\n")
+	default:
+		if m.notice != "" {
+			content.WriteString(styles.SuccessStyle.Render(m.notice) + "\n")
+		}
+	}
+
 	// Error message
 	if errorMsg != "" {
 		content.WriteString(styles.ErrorStyle.Render("Error: "+errorMsg) + "\n")
@@ -131,12 +259,21 @@ func (m DashboardModel) View(width, height int, agents []client.Agent, selectedA
 	content.WriteString(m.table.View() + "\n")
 
 	// Help
-	helpText := "↑/↓: Navigate | Enter: View Details | d: Details | c: Conversation | f: Follow-up | r: Refresh | q: Quit"
+	helpText := "↑/↓: Navigate | Enter: View Details | d: Details | c: Conversation | f: Follow-up | x: Stop | D: Delete | r: Refresh | q: Quit"
 	content.WriteString(styles.HelpStyle.Render(helpText))
 
 	return styles.BaseStyle.Width(width).Height(height).Render(content.String())
 }
 
+// currentAgent returns the agent on the highlighted row, if any
+func (m DashboardModel) currentAgent() (client.Agent, bool) {
+	row := m.table.Cursor()
+	if row < 0 || row >= len(m.filteredAgents) {
+		return client.Agent{}, false
+	}
+	return m.filteredAgents[row], true
+}
+
 // updateTable updates the table with new agent data
 func (m *DashboardModel) updateTable(agents []client.Agent) {
 	m.allAgents = agents // Store all agents
@@ -145,8 +282,7 @@ func (m *DashboardModel) updateTable(agents []client.Agent) {
 	var filteredAgents []client.Agent
 
 	for _, agent := range agents {
-		// Filter expired agents if not showing all
-		if !m.showAll && agent.Status == "EXPIRED" {
+		if !m.filter.Match(agent) {
 			continue
 		}
 
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/filter"
+)
+
+// pagedServer is a fake API listing agents a page at a time, with the
+// statuses of each page given up front
+type pagedServer struct {
+	pages [][]string
+
+	mu       sync.Mutex
+	requests int
+}
+
+func (s *pagedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+	var items []string
+	for i, status := range s.pages[page] {
+		items = append(items, fmt.Sprintf(`{"id":"bc_%d_%d","name":"Agent","status":%q}`, page, i, status))
+	}
+	next := ""
+	if page+1 < len(s.pages) {
+		next = strconv.Itoa(page + 1)
+	}
+	fmt.Fprintf(w, `{"agents":[%s],"nextCursor":%q}`, strings.Join(items, ","), next)
+}
+
+// repeat returns n copies of status
+func repeat(status string, n int) []string {
+	statuses := make([]string, n)
+	for i := range statuses {
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// runDashboard loads the agents into a dashboard of the given terminal
+// height filtered to failed agents, running the commands the model returns
+// until it settles
+func runDashboard(t *testing.T, srv *pagedServer, height int) Model {
+	t.Helper()
+	server := httptest.NewServer(srv)
+	t.Cleanup(server.Close)
+
+	apiClient, err := client.New("test-key", client.WithBaseURL(server.URL), client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	f, err := filter.New(filter.Options{Statuses: []string{client.StatusFailed}}, time.Now())
+	if err != nil {
+		t.Fatalf("filter.New: %v", err)
+	}
+
+	var m tea.Model = NewModel(context.Background(), apiClient).WithFilter(f)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: height})
+
+	queue := []tea.Msg{m.(Model).fetchAgents()()}
+	for steps := 0; len(queue) > 0; steps++ {
+		if steps > 50 {
+			t.Fatal("dashboard keeps loading agents")
+		}
+		var cmd tea.Cmd
+		m, cmd = m.Update(queue[0])
+		queue = append(queue[1:], pagingMsgs(cmd)...)
+	}
+	return m.(Model)
+}
+
+// pagingMsgs runs cmd, including batched commands, and returns the
+// messages about loading agents it produces
+func pagingMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		var msgs []tea.Msg
+		for _, c := range msg {
+			msgs = append(msgs, pagingMsgs(c)...)
+		}
+		return msgs
+	case AgentsMsg, LoadMoreAgentsMsg, MoreAgentsMsg, ErrorMsg:
+		return []tea.Msg{msg}
+	default:
+		return nil
+	}
+}
+
+func TestDashboardLoadsPagesToFillFilteredTable(t *testing.T) {
+	tests := []struct {
+		name         string
+		pages        [][]string
+		height       int
+		wantRows     int
+		wantRequests int
+	}{
+		{
+			name:         "matches on later pages",
+			pages:        [][]string{repeat("COMPLETED", 100), repeat("COMPLETED", 100), append(repeat("FAILED", 3), "RUNNING")},
+			height:       20,
+			wantRows:     3,
+			wantRequests: 3,
+		},
+		{
+			name:         "stops once the table is full",
+			pages:        [][]string{repeat("FAILED", 4), repeat("FAILED", 8), repeat("FAILED", 8), repeat("FAILED", 8)},
+			height:       20,
+			wantRows:     12,
+			wantRequests: 2,
+		},
+		{
+			name:         "no more pages",
+			pages:        [][]string{append(repeat("COMPLETED", 5), "FAILED")},
+			height:       20,
+			wantRows:     1,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &pagedServer{pages: tt.pages}
+			m := runDashboard(t, srv, tt.height)
+
+			if got := len(m.dashboard.filteredAgents); got != tt.wantRows {
+				t.Errorf("dashboard shows %d agents, want %d", got, tt.wantRows)
+			}
+			if srv.requests != tt.wantRequests {
+				t.Errorf("server received %d list requests, want %d", srv.requests, tt.wantRequests)
+			}
+			if m.dashboard.loadingMore {
+				t.Error("dashboard is still loading agents")
+			}
+		})
+	}
+}
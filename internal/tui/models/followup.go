@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textarea"
@@ -19,6 +21,12 @@ type FollowupModel struct {
 	sending     bool
 	sent        bool
 	error       string
+
+	// Image attachments, added by typing a file path after Ctrl+A
+	pathInput  textinput.Model
+	attaching  bool
+	images     []client.Image
+	imageNames []string
 }
 
 // NewFollowupModel creates a new followup model
@@ -32,13 +40,19 @@ func NewFollowupModel() FollowupModel {
 
 	// Text area for longer messages
 	ta := textarea.New()
-	ta.Placeholder = "Enter your follow-up message (press Ctrl+Enter to send)..."
+	ta.Placeholder = "Enter your follow-up message (press Ctrl+S to send)..."
 	ta.SetWidth(70)
 	ta.SetHeight(8)
 
+	// Path input for image attachments
+	pi := textinput.New()
+	pi.Placeholder = "Path to a PNG, JPEG or GIF image..."
+	pi.Width = 50
+
 	return FollowupModel{
 		textarea:    ta,
 		textinput:   ti,
+		pathInput:   pi,
 		useTextarea: false,
 	}
 }
@@ -50,7 +64,32 @@ func (m FollowupModel) Update(msg tea.Msg) (FollowupModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.attaching {
+			return m.updateAttaching(msg)
+		}
+
 		switch msg.String() {
+		case "ctrl+a":
+			if m.sending {
+				return m, nil
+			}
+			if len(m.images) >= client.MaxImages {
+				m.error = fmt.Sprintf("at most %d images can be attached", client.MaxImages)
+				return m, nil
+			}
+			m.attaching = true
+			m.error = ""
+			m.pathInput.SetValue("")
+			m.pathInput.Focus()
+			return m, textinput.Blink
+
+		case "ctrl+x":
+			if !m.sending && len(m.images) > 0 {
+				m.images = m.images[:len(m.images)-1]
+				m.imageNames = m.imageNames[:len(m.imageNames)-1]
+			}
+			return m, nil
+
 		case "ctrl+t":
 			// Toggle between text input and textarea
 			m.useTextarea = !m.useTextarea
@@ -63,29 +102,43 @@ func (m FollowupModel) Update(msg tea.Msg) (FollowupModel, tea.Cmd) {
 			}
 
 		case "enter":
-			if !m.useTextarea && m.textinput.Value() != "" && !m.sending {
-				return m, m.sendMessage(m.textinput.Value())
+			if !m.useTextarea && strings.TrimSpace(m.textinput.Value()) != "" && !m.sending {
+				return m.sendMessage(m.textinput.Value())
 			}
 
-		case "ctrl+enter":
-			if m.useTextarea && m.textarea.Value() != "" && !m.sending {
-				return m, m.sendMessage(m.textarea.Value())
+		case "ctrl+s", "ctrl+enter":
+			// Most terminals cannot distinguish Ctrl+Enter from Enter, so
+			// Ctrl+S is the primary send key for long messages
+			if m.useTextarea && strings.TrimSpace(m.textarea.Value()) != "" && !m.sending {
+				return m.sendMessage(m.textarea.Value())
 			}
+			return m, nil
+		}
 
-		case "esc":
-			if !m.sending {
-				m.reset()
-			}
+		// Typing a new message clears the result of the previous one
+		if !m.sending {
+			m.sent = false
 		}
 
 	case FollowupSentMsg:
 		m.sending = false
 		m.sent = true
 		m.error = ""
+		m.textinput.SetValue("")
+		m.textarea.SetValue("")
+		m.images = nil
+		m.imageNames = nil
+		return m, nil
 
-	case ErrorMsg:
+	case FollowupErrorMsg:
 		m.sending = false
 		m.error = msg.Error
+		return m, nil
+	}
+
+	// Ignore edits while a message is in flight
+	if m.sending {
+		return m, nil
 	}
 
 	// Update the appropriate input
@@ -99,6 +152,45 @@ func (m FollowupModel) Update(msg tea.Msg) (FollowupModel, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// updateAttaching handles keys while the image path input is open
+func (m FollowupModel) updateAttaching(msg tea.KeyMsg) (FollowupModel, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		path := strings.TrimSpace(m.pathInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+
+		image, err := client.LoadImage(path)
+		if err != nil {
+			m.error = err.Error()
+			return m, nil
+		}
+
+		m.images = append(m.images, image)
+		m.imageNames = append(m.imageNames, filepath.Base(path))
+		m.error = ""
+		m.attaching = false
+		m.pathInput.Blur()
+		return m, nil
+
+	case "esc":
+		m.attaching = false
+		m.error = ""
+		m.pathInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.pathInput, cmd = m.pathInput.Update(msg)
+	return m, cmd
+}
+
 // View renders the followup view
 func (m FollowupModel) View(width, height int, agent *client.Agent, errorMsg string) string {
 	if agent == nil {
@@ -130,49 +222,57 @@ func (m FollowupModel) View(width, height int, agent *client.Agent, errorMsg str
 	}
 
 	// Instructions
-	if !m.sent {
-		content.WriteString(styles.InfoStyle.Render("Enter additional instructions for the agent:") + "\n\n")
+	content.WriteString(styles.InfoStyle.Render("Enter additional instructions for the agent:") + "\n\n")
 
-		// Input mode toggle
-		inputMode := "Short message"
-		if m.useTextarea {
-			inputMode = "Long message"
-		}
-		content.WriteString(styles.HelpStyle.Render(fmt.Sprintf("Mode: %s (Ctrl+T to toggle)", inputMode)) + "\n\n")
+	// Input mode toggle
+	inputMode := "Short message"
+	if m.useTextarea {
+		inputMode = "Long message"
+	}
+	content.WriteString(styles.HelpStyle.Render(fmt.Sprintf("Mode: %s (Ctrl+T to toggle)", inputMode)) + "\n\n")
 
-		// Input field
-		if m.useTextarea {
-			content.WriteString(m.textarea.View() + "\n")
-		} else {
-			content.WriteString(m.textinput.View() + "\n\n")
-		}
+	// Input field
+	if m.useTextarea {
+		content.WriteString(m.textarea.View() + "\n")
+	} else {
+		content.WriteString(m.textinput.View() + "\n\n")
+	}
 
-		// Sending status
-		if m.sending {
-			content.WriteString(styles.InfoStyle.Render("📤 Sending follow-up message...") + "\n")
-		}
+	// Attachments
+	if len(m.imageNames) > 0 {
+		content.WriteString(styles.InfoStyle.Render(fmt.Sprintf("🖼️  Attached (%d/%d): %s", len(m.images), client.MaxImages, strings.Join(m.imageNames, ", "))) + "\n")
+	}
+	if m.attaching {
+		content.WriteString(styles.HelpStyle.Render("Attach image (Enter to add, Esc to cancel):") + "\n")
+		content.WriteString(m.pathInput.View() + "\n")
+	}
+
+	// Sending status
+	if m.sending {
+		content.WriteString(styles.InfoStyle.Render("📤 Sending follow-up message...") + "\n")
 	}
 
 	// Help
 	var helpText string
-	if m.sent {
-		helpText = "Esc: Back | q: Quit"
-	} else if m.useTextarea {
-		helpText = "Ctrl+Enter: Send | Ctrl+T: Toggle input mode | Esc: Back | q: Quit"
+	if m.useTextarea {
+		helpText = "Ctrl+S: Send | Ctrl+T: Toggle input mode | Ctrl+A: Attach image | Ctrl+X: Remove image | Esc: Back | Ctrl+C: Quit"
 	} else {
-		helpText = "Enter: Send | Ctrl+T: Toggle input mode | Esc: Back | q: Quit"
+		helpText = "Enter: Send | Ctrl+T: Toggle input mode | Ctrl+A: Attach image | Ctrl+X: Remove image | Esc: Back | Ctrl+C: Quit"
 	}
 	content.WriteString("\n" + styles.HelpStyle.Render(helpText))
 
 	return styles.BaseStyle.Width(width).Height(height).Render(content.String())
 }
 
-// sendMessage creates a command to send a followup message
-func (m FollowupModel) sendMessage(message string) tea.Cmd {
+// sendMessage marks the model as sending and asks the main model to deliver
+// the message through the API
+func (m FollowupModel) sendMessage(message string) (FollowupModel, tea.Cmd) {
 	m.sending = true
-	return func() tea.Msg {
-		// This will be handled by the main model
-		return FollowupSentMsg{Message: message}
+	m.sent = false
+	m.error = ""
+	images := m.images
+	return m, func() tea.Msg {
+		return SendFollowupMsg{Message: message, Images: images}
 	}
 }
 
@@ -183,4 +283,9 @@ func (m *FollowupModel) reset() {
 	m.sending = false
 	m.sent = false
 	m.error = ""
+	m.attaching = false
+	m.images = nil
+	m.imageNames = nil
+	m.pathInput.SetValue("")
+	m.pathInput.Blur()
 }
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+)
+
+// followupServer is a fake API recording the follow-ups it receives
+type followupServer struct {
+	status int
+
+	mu       sync.Mutex
+	requests []recordedFollowup
+}
+
+// recordedFollowup is a follow-up request as received by followupServer
+type recordedFollowup struct {
+	Method string
+	Path   string
+	Body   client.FollowupRequest
+}
+
+func (s *followupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body client.FollowupRequest
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recordedFollowup{Method: r.Method, Path: r.URL.Path, Body: body})
+	s.mu.Unlock()
+
+	if s.status != http.StatusOK {
+		w.WriteHeader(s.status)
+		fmt.Fprint(w, `{"error":"agent is not running"}`)
+		return
+	}
+	fmt.Fprint(w, `{"id":"bc_abc123"}`)
+}
+
+// newFollowupModel returns a model whose client talks to srv, with an agent
+// selected
+func newFollowupModel(t *testing.T, srv *followupServer) Model {
+	t.Helper()
+	server := httptest.NewServer(srv)
+	t.Cleanup(server.Close)
+
+	apiClient, err := client.New("test-key",
+		client.WithBaseURL(server.URL),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+
+	m := NewModel(context.Background(), apiClient)
+	updated, _ := m.Update(AgentSelectedMsg{Agent: client.Agent{ID: "bc_abc123", Name: "Fix login", Status: client.StatusRunning}})
+	return updated.(Model)
+}
+
+// sendFollowup drives msg through the model and runs the command it returns
+func sendFollowup(t *testing.T, m Model, msg SendFollowupMsg) tea.Msg {
+	t.Helper()
+	_, cmd := m.Update(msg)
+	if cmd == nil {
+		t.Fatal("Update(SendFollowupMsg) returned no command")
+	}
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow-up command did not finish")
+		return nil
+	}
+}
+
+func TestSendFollowupReachesServer(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    SendFollowupMsg
+		images int
+	}{
+		{name: "text", msg: SendFollowupMsg{Message: "Also update the docs"}},
+		{name: "with image", msg: SendFollowupMsg{
+			Message: "Match this mockup",
+			Images:  []client.Image{{Data: "iVBORw0KGgo=", Dimension: client.Dimension{Width: 1, Height: 1}}},
+		}, images: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &followupServer{status: http.StatusOK}
+			m := newFollowupModel(t, srv)
+
+			result := sendFollowup(t, m, tt.msg)
+
+			sent, ok := result.(FollowupSentMsg)
+			if !ok {
+				t.Fatalf("command returned %T (%+v), want FollowupSentMsg", result, result)
+			}
+			if sent.AgentID != "bc_abc123" || sent.Message != tt.msg.Message {
+				t.Errorf("FollowupSentMsg = %+v, want agent bc_abc123 and message %q", sent, tt.msg.Message)
+			}
+
+			if len(srv.requests) != 1 {
+				t.Fatalf("server received %d requests, want 1", len(srv.requests))
+			}
+			req := srv.requests[0]
+			if req.Method != http.MethodPost || req.Path != "/agents/bc_abc123/followup" {
+				t.Errorf("request = %s %s, want POST /agents/bc_abc123/followup", req.Method, req.Path)
+			}
+			if req.Body.Prompt.Text != tt.msg.Message {
+				t.Errorf("prompt text = %q, want %q", req.Body.Prompt.Text, tt.msg.Message)
+			}
+			if len(req.Body.Prompt.Images) != tt.images {
+				t.Errorf("prompt has %d images, want %d", len(req.Body.Prompt.Images), tt.images)
+			}
+		})
+	}
+}
+
+func TestSendFollowupReportsAPIError(t *testing.T) {
+	srv := &followupServer{status: http.StatusConflict}
+	m := newFollowupModel(t, srv)
+
+	result := sendFollowup(t, m, SendFollowupMsg{Message: "Keep going"})
+
+	failed, ok := result.(FollowupErrorMsg)
+	if !ok {
+		t.Fatalf("command returned %T (%+v), want FollowupErrorMsg", result, result)
+	}
+	if failed.Error == "" {
+		t.Error("FollowupErrorMsg has no error text")
+	}
+	if len(srv.requests) != 1 || srv.requests[0].Path != "/agents/bc_abc123/followup" {
+		t.Errorf("server received %+v, want one follow-up request", srv.requests)
+	}
+}
+
+func TestSendFollowupWithoutAgent(t *testing.T) {
+	srv := &followupServer{status: http.StatusOK}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	apiClient, err := client.New("test-key", client.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	m := NewModel(context.Background(), apiClient)
+
+	updated, _ := m.Update(SendFollowupMsg{Message: "Hello"})
+	if got := updated.(Model).followup.error; got == "" {
+		t.Error("composer shows no error when no agent is selected")
+	}
+	if len(srv.requests) != 0 {
+		t.Errorf("server received %d requests, want none", len(srv.requests))
+	}
+}
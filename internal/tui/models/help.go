@@ -1,7 +1,9 @@
 package models
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/tui/styles"
@@ -21,7 +23,7 @@ func (m HelpModel) Update(msg tea.Msg) (HelpModel, tea.Cmd) {
 }
 
 // View renders the help view
-func (m HelpModel) View(width, height int, keyMap KeyMap) string {
+func (m HelpModel) View(width, height int, keyMap KeyMap, refreshInterval time.Duration) string {
 	var content strings.Builder
 
 	// Header
@@ -48,6 +50,8 @@ func (m HelpModel) View(width, height int, keyMap KeyMap) string {
 		{"d", "View agent details"},
 		{"c", "View conversation"},
 		{"f", "Send follow-up (running agents)"},
+		{"x", "Stop selected agent (running agents)"},
+		{"D", "Delete selected agent"},
 		{"s", "Open settings"},
 		{"?", "Show this help"},
 	}
@@ -58,7 +62,8 @@ func (m HelpModel) View(width, height int, keyMap KeyMap) string {
 	viewsHelp := [][]string{
 		{"Details View", "Scroll with ↑/↓, view agent information"},
 		{"Conversation", "Scroll through message history"},
-		{"Follow-up", "Ctrl+T to toggle input mode"},
+		{"Follow-up", "Enter (or Ctrl+S in long mode) to send, Ctrl+T to toggle input mode"},
+		{"Attachments", "Ctrl+A to attach an image in the follow-up view, Ctrl+X to remove the last one"},
 		{"Settings", "Configure application preferences"},
 	}
 	content.WriteString(m.renderHelpTable(viewsHelp) + "\n\n")
@@ -66,11 +71,13 @@ func (m HelpModel) View(width, height int, keyMap KeyMap) string {
 	// Tips section
 	content.WriteString(styles.TitleStyle.Render("💡 Tips") + "\n")
 	tips := []string{
-		"• Agents auto-refresh every 30 seconds",
+		fmt.Sprintf("• Agents auto-refresh every %s (refresh_interval setting)", refreshInterval),
+		"• Open conversations check for new messages every 5 seconds",
 		"• Use 't' in dashboard to filter expired agents",
+		"• Scroll past the last agent in the dashboard to load the next page",
 		"• Follow-up messages can only be sent to running agents",
 		"• Press Ctrl+T in follow-up view to switch between short and long message input",
-		"• Configuration is saved in ~/.cursor-cli.yaml",
+		"• Configuration is saved in ~/.config/cursor-cli/config.yaml",
 	}
 	for _, tip := range tips {
 		content.WriteString(styles.TableCellStyle.Render(tip) + "\n")
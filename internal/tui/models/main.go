@@ -1,13 +1,24 @@
 package models
 
 import (
+	"context"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/filter"
 )
 
+// DefaultRefreshInterval is how often the agent list is refreshed when no
+// refresh interval is configured
+const DefaultRefreshInterval = 30 * time.Second
+
+// conversationRefreshInterval is how often an open conversation is polled
+// for new messages. Unchanged conversations are only revalidated, so this
+// can be much shorter than the agent list refresh.
+const conversationRefreshInterval = 5 * time.Second
+
 // View represents the different views in the TUI
 type View int
 
@@ -27,14 +38,22 @@ type Model struct {
 	width       int
 	height      int
 
-	// API client
+	// API client and the context its requests run under; cancel aborts
+	// any in-flight requests when the TUI quits
 	client *client.Client
+	ctx    context.Context
+	cancel context.CancelFunc
 
 	// Data
 	agents        []client.Agent
+	nextCursor    string
 	selectedAgent *client.Agent
 	conversation  *client.ConversationResponse
 
+	// conversationTail follows the conversation being shown
+	conversationTail        *client.ConversationTail
+	lastConversationRefresh time.Time
+
 	// Sub-models
 	dashboard         DashboardModel
 	details           DetailsModel
@@ -44,10 +63,11 @@ type Model struct {
 	help              HelpModel
 
 	// State
-	loading     bool
-	error       string
-	lastRefresh time.Time
-	autoRefresh bool
+	loading         bool
+	error           string
+	lastRefresh     time.Time
+	autoRefresh     bool
+	refreshInterval time.Duration
 
 	// Key bindings
 	keyMap KeyMap
@@ -70,6 +90,8 @@ type KeyMap struct {
 	Followup     key.Binding
 	Settings     key.Binding
 	Toggle       key.Binding
+	Stop         key.Binding
+	Delete       key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -135,16 +157,28 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("t"),
 			key.WithHelp("t", "toggle"),
 		),
+		Stop: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "stop agent"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "delete agent"),
+		),
 	}
 }
 
 // NewModel creates a new TUI model
-func NewModel(apiClient *client.Client) Model {
+func NewModel(ctx context.Context, apiClient *client.Client) Model {
+	ctx, cancel := context.WithCancel(ctx)
 	m := Model{
-		currentView: DashboardView,
-		client:      apiClient,
-		keyMap:      DefaultKeyMap(),
-		autoRefresh: true,
+		currentView:     DashboardView,
+		client:          apiClient,
+		ctx:             ctx,
+		cancel:          cancel,
+		keyMap:          DefaultKeyMap(),
+		autoRefresh:     true,
+		refreshInterval: DefaultRefreshInterval,
 	}
 
 	// Initialize sub-models
@@ -158,6 +192,22 @@ func NewModel(apiClient *client.Client) Model {
 	return m
 }
 
+// WithRefreshInterval returns the model with the agent list refreshed every
+// d instead of DefaultRefreshInterval
+func (m Model) WithRefreshInterval(d time.Duration) Model {
+	if d > 0 {
+		m.refreshInterval = d
+	}
+	return m
+}
+
+// WithFilter returns the model with the dashboard showing only the agents
+// matching f
+func (m Model) WithFilter(f filter.Filter) Model {
+	m.dashboard = m.dashboard.WithFilter(f)
+	return m
+}
+
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
@@ -176,10 +226,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+		// The dashboard sizes its table even while another view is shown
+		if m.currentView != DashboardView {
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+
 	case tea.KeyMsg:
+		// The follow-up composer needs every printable key for typing, so
+		// only Ctrl+C and Esc act globally there
+		if m.currentView == FollowupView {
+			switch msg.String() {
+			case "ctrl+c":
+				m.cancel()
+				return m, tea.Quit
+			case "esc":
+				if !m.followup.attaching {
+					if !m.followup.sending {
+						m.currentView = DashboardView
+					}
+					return m, nil
+				}
+			}
+			m.followup, cmd = m.followup.Update(msg)
+			return m, cmd
+		}
+
 		// Only handle truly global keys that should work everywhere
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m.cancel()
 			return m, tea.Quit
 
 		case "?", "f1":
@@ -205,13 +281,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "c":
 			if m.selectedAgent != nil {
 				m.currentView = ConversationView
-				cmd = m.fetchConversation(m.selectedAgent.ID)
+				cmd = m.followConversation(m.selectedAgent.ID)
 				cmds = append(cmds, cmd)
 				return m, tea.Batch(cmds...)
 			}
 
 		case "f":
 			if m.selectedAgent != nil && m.selectedAgent.Status == "RUNNING" {
+				if m.currentView != FollowupView {
+					m.followup.reset()
+				}
 				m.currentView = FollowupView
 				return m, tea.Batch(cmds...)
 			}
@@ -225,17 +304,88 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case AgentsMsg:
 		m.agents = msg.Agents
+		m.nextCursor = msg.NextCursor
 		m.loading = false
 		m.error = ""
 		m.lastRefresh = time.Now()
 
-		// Update dashboard
-		m.dashboard, cmd = m.dashboard.Update(msg)
+		// Update dashboard, unless it is the current view and gets the
+		// message below
+		if m.currentView != DashboardView {
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+
+	case ConversationMessagesMsg:
+		// Drop late results for a conversation that is no longer shown
+		if m.conversationTail == nil || m.conversationTail.AgentID() != msg.AgentID {
+			break
+		}
+		if m.conversation == nil {
+			m.conversation = &client.ConversationResponse{Messages: []client.Message{}}
+		} else if len(msg.Messages) == 0 {
+			break
+		}
+		m.conversation.Messages = append(m.conversation.Messages, msg.Messages...)
+		m.conversationModel, cmd = m.conversationModel.Update(ConversationMsg{Conversation: *m.conversation})
 		cmds = append(cmds, cmd)
 
-	case ConversationMsg:
-		m.conversation = &msg.Conversation
-		m.conversationModel, cmd = m.conversationModel.Update(msg)
+	case LoadMoreAgentsMsg:
+		if m.nextCursor != "" {
+			cmd = m.fetchMoreAgents(m.nextCursor)
+			cmds = append(cmds, cmd)
+		}
+
+	case MoreAgentsMsg:
+		m.agents = append(m.agents, msg.Agents...)
+		m.nextCursor = msg.NextCursor
+		m.error = ""
+
+		// Hand the combined list to the dashboard
+		m.dashboard, cmd = m.dashboard.Update(AgentsMsg{Agents: m.agents, NextCursor: m.nextCursor, CachedAt: m.client.CachedAt()})
+		cmds = append(cmds, cmd)
+
+	case SendFollowupMsg:
+		if m.selectedAgent == nil {
+			m.followup, cmd = m.followup.Update(FollowupErrorMsg{Error: "no agent selected"})
+			return m, cmd
+		}
+		return m, m.sendFollowup(m.selectedAgent.ID, msg.Message, msg.Images)
+
+	case FollowupSentMsg, FollowupErrorMsg:
+		// Always deliver the result to the composer, even if the user has
+		// navigated away while the message was being sent
+		m.followup, cmd = m.followup.Update(msg)
+		cmds = append(cmds, cmd)
+		if sent, ok := msg.(FollowupSentMsg); ok {
+			if m.conversationTail != nil && m.conversationTail.AgentID() == sent.AgentID {
+				m.lastConversationRefresh = time.Now()
+				cmds = append(cmds, m.fetchConversation(m.conversationTail))
+			} else {
+				cmds = append(cmds, m.followConversation(sent.AgentID))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case StopAgentMsg:
+		cmd = m.stopAgent(msg.Agent.ID)
+		cmds = append(cmds, cmd)
+
+	case DeleteAgentMsg:
+		cmd = m.deleteAgent(msg.Agent.ID)
+		cmds = append(cmds, cmd)
+
+	case AgentStoppedMsg:
+		m.error = ""
+		cmd = m.fetchAgents()
+		cmds = append(cmds, cmd)
+
+	case AgentDeletedMsg:
+		m.error = ""
+		if m.selectedAgent != nil && m.selectedAgent.ID == msg.AgentID {
+			m.selectedAgent = nil
+		}
+		cmd = m.fetchAgents()
 		cmds = append(cmds, cmd)
 
 	case ErrorMsg:
@@ -243,10 +393,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 
 	case TickMsg:
-		if m.autoRefresh && time.Since(m.lastRefresh) > 30*time.Second {
+		if m.autoRefresh && time.Since(m.lastRefresh) > m.refreshInterval {
 			cmd = m.fetchAgents()
 			cmds = append(cmds, cmd)
 		}
+		if m.autoRefresh && m.currentView == ConversationView && m.conversationTail != nil &&
+			time.Since(m.lastConversationRefresh) > conversationRefreshInterval {
+			m.lastConversationRefresh = time.Now()
+			cmd = m.fetchConversation(m.conversationTail)
+			cmds = append(cmds, cmd)
+		}
 		cmd = m.tickCmd()
 		cmds = append(cmds, cmd)
 
@@ -292,9 +448,9 @@ func (m Model) View() string {
 	case FollowupView:
 		return m.followup.View(m.width, m.height, m.selectedAgent, m.error)
 	case SettingsView:
-		return m.settings.View(m.width, m.height, m.autoRefresh, m.error)
+		return m.settings.View(m.width, m.height, m.autoRefresh, m.refreshInterval, m.error)
 	case HelpView:
-		return m.help.View(m.width, m.height, m.keyMap)
+		return m.help.View(m.width, m.height, m.keyMap, m.refreshInterval)
 	default:
 		return "Unknown view"
 	}
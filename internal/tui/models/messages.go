@@ -9,7 +9,21 @@ import (
 
 // AgentsMsg represents a message containing agents data
 type AgentsMsg struct {
-	Agents []client.Agent
+	Agents     []client.Agent
+	NextCursor string
+
+	// CachedAt is when the oldest agent was fetched if the agents were
+	// read from the local cache in offline mode
+	CachedAt time.Time
+}
+
+// LoadMoreAgentsMsg requests the next page of agents
+type LoadMoreAgentsMsg struct{}
+
+// MoreAgentsMsg represents an additional page of agents
+type MoreAgentsMsg struct {
+	Agents     []client.Agent
+	NextCursor string
 }
 
 // ConversationMsg represents a message containing conversation data
@@ -17,6 +31,13 @@ type ConversationMsg struct {
 	Conversation client.ConversationResponse
 }
 
+// ConversationMessagesMsg carries the messages added to the followed
+// conversation since it was last fetched
+type ConversationMessagesMsg struct {
+	AgentID  string
+	Messages []client.Message
+}
+
 // ErrorMsg represents an error message
 type ErrorMsg struct {
 	Error string
@@ -30,45 +51,128 @@ type AgentSelectedMsg struct {
 	Agent client.Agent
 }
 
+// SendFollowupMsg requests that a followup message be sent to the selected agent
+type SendFollowupMsg struct {
+	Message string
+	Images  []client.Image
+}
+
 // FollowupSentMsg represents a successful followup message
 type FollowupSentMsg struct {
 	AgentID string
 	Message string
 }
 
-// fetchAgents fetches agents from the API
+// FollowupErrorMsg represents a followup message that could not be sent
+type FollowupErrorMsg struct {
+	Error string
+}
+
+// StopAgentMsg requests that the given agent be stopped
+type StopAgentMsg struct {
+	Agent client.Agent
+}
+
+// DeleteAgentMsg requests that the given agent be deleted
+type DeleteAgentMsg struct {
+	Agent client.Agent
+}
+
+// AgentStoppedMsg represents a successfully stopped agent
+type AgentStoppedMsg struct {
+	AgentID string
+}
+
+// AgentDeletedMsg represents a successfully deleted agent
+type AgentDeletedMsg struct {
+	AgentID string
+}
+
+// fetchAgents fetches agents from the API. It reloads as many pages as are
+// currently shown so a refresh does not drop pages loaded by scrolling.
 func (m Model) fetchAgents() tea.Cmd {
+	want := len(m.agents)
 	return tea.Cmd(func() tea.Msg {
-		response, err := m.client.ListAgents(100, "")
+		pager := m.client.NewPager(client.MaxPageSize, "")
+
+		var agents []client.Agent
+		for !pager.Done() && (len(agents) == 0 || len(agents) < want) {
+			page, err := pager.Next(m.ctx)
+			if err != nil {
+				return ErrorMsg{Error: err.Error()}
+			}
+			agents = append(agents, page...)
+		}
+		return AgentsMsg{Agents: agents, NextCursor: pager.Cursor(), CachedAt: m.client.CachedAt()}
+	})
+}
+
+// fetchMoreAgents fetches the page of agents starting at cursor
+func (m Model) fetchMoreAgents(cursor string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		response, err := m.client.ListAgentsContext(m.ctx, client.MaxPageSize, cursor)
 		if err != nil {
 			return ErrorMsg{Error: err.Error()}
 		}
-		return AgentsMsg{Agents: response.Agents}
+		return MoreAgentsMsg{Agents: response.Agents, NextCursor: response.NextCursor}
 	})
 }
 
-// fetchConversation fetches conversation for a specific agent
-func (m Model) fetchConversation(agentID string) tea.Cmd {
+// fetchConversation fetches the messages added to the followed
+// conversation since the last fetch
+func (m Model) fetchConversation(tail *client.ConversationTail) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		conversation, err := m.client.GetAgentConversation(agentID)
+		messages, err := tail.Next(m.ctx)
 		if err != nil {
 			return ErrorMsg{Error: err.Error()}
 		}
-		return ConversationMsg{Conversation: *conversation}
+		return ConversationMessagesMsg{AgentID: tail.AgentID(), Messages: messages}
 	})
 }
 
-// sendFollowup sends a followup message to an agent
-func (m Model) sendFollowup(agentID, message string) tea.Cmd {
+// followConversation starts following the conversation of agentID,
+// replacing the one shown, and fetches its messages
+func (m *Model) followConversation(agentID string) tea.Cmd {
+	m.conversation = nil
+	m.conversationTail = m.client.TailConversation(agentID)
+	m.lastConversationRefresh = time.Now()
+	return m.fetchConversation(m.conversationTail)
+}
+
+// sendFollowup sends a followup message, with optional images, to an agent
+func (m Model) sendFollowup(agentID, message string, images []client.Image) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		_, err := m.client.AddFollowup(agentID, message)
+		prompt := client.Prompt{Text: message, Images: images}
+		_, err := m.client.AddFollowupPromptContext(m.ctx, agentID, prompt)
 		if err != nil {
-			return ErrorMsg{Error: err.Error()}
+			return FollowupErrorMsg{Error: err.Error()}
 		}
 		return FollowupSentMsg{AgentID: agentID, Message: message}
 	})
 }
 
+// stopAgent stops a running agent
+func (m Model) stopAgent(agentID string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		_, err := m.client.StopAgentContext(m.ctx, agentID)
+		if err != nil {
+			return ErrorMsg{Error: err.Error()}
+		}
+		return AgentStoppedMsg{AgentID: agentID}
+	})
+}
+
+// deleteAgent deletes an agent
+func (m Model) deleteAgent(agentID string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		_, err := m.client.DeleteAgentContext(m.ctx, agentID)
+		if err != nil {
+			return ErrorMsg{Error: err.Error()}
+		}
+		return AgentDeletedMsg{AgentID: agentID}
+	})
+}
+
 // tickCmd returns a command that sends a tick message every second
 func (m Model) tickCmd() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/tui/styles"
@@ -58,7 +59,7 @@ func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 }
 
 // View renders the settings view
-func (m SettingsModel) View(width, height int, autoRefresh bool, errorMsg string) string {
+func (m SettingsModel) View(width, height int, autoRefresh bool, refreshInterval time.Duration, errorMsg string) string {
 	var content strings.Builder
 
 	// Header
@@ -85,7 +86,7 @@ func (m SettingsModel) View(width, height int, autoRefresh bool, errorMsg string
 			}
 			line = fmt.Sprintf("%s: %s", option, status)
 		case 1: // Refresh interval
-			line = fmt.Sprintf("%s: 30 seconds", option)
+			line = fmt.Sprintf("%s: %s", option, refreshInterval)
 		case 2: // Show expired agents
 			line = fmt.Sprintf("%s: Dashboard setting", option)
 		case 3: // API key info
@@ -107,7 +108,7 @@ func (m SettingsModel) View(width, height int, autoRefresh bool, errorMsg string
 	content.WriteString("\n")
 
 	// Additional info
-	content.WriteString(styles.InfoStyle.Render("Configuration is stored in ~/.cursor-cli.yaml") + "\n\n")
+	content.WriteString(styles.InfoStyle.Render("Change settings with 'cursor-cli config set'") + "\n\n")
 
 	// Help
 	helpText := "↑/↓: Navigate | Enter/Space: Select | Esc: Back | q: Quit"
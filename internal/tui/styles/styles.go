@@ -93,6 +93,12 @@ var (
 			Bold(true).
 			Margin(1, 0)
 
+	// Warning message styles
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(Warning).
+			Bold(true).
+			Margin(1, 0)
+
 	// Info message styles
 	InfoStyle = lipgloss.NewStyle().
 			Foreground(Secondary).
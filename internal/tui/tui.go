@@ -1,25 +1,54 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/client"
+	"github.com/satishbabariya/cursor-background-agent-cli/internal/filter"
 	"github.com/satishbabariya/cursor-background-agent-cli/internal/tui/models"
 )
 
-// Run starts the TUI application
-func Run(apiClient *client.Client) error {
-	model := models.NewModel(apiClient)
+// Options configures the TUI
+type Options struct {
+	// RefreshInterval is how often the agent list is refreshed; zero
+	// means models.DefaultRefreshInterval
+	RefreshInterval time.Duration
+
+	// Theme is the color theme: "default", or "mono" to disable colors
+	Theme string
+
+	// Filter selects the agents shown on the dashboard
+	Filter filter.Filter
+}
+
+// Run starts the TUI application. The program exits when ctx is cancelled.
+func Run(ctx context.Context, apiClient *client.Client, opts Options) error {
+	if opts.Theme == "mono" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	model := models.NewModel(ctx, apiClient).
+		WithRefreshInterval(opts.RefreshInterval).
+		WithFilter(opts.Filter)
 
 	p := tea.NewProgram(
 		model,
+		tea.WithContext(ctx),
 		tea.WithAltScreen(),       // Use alternate screen buffer
 		tea.WithMouseCellMotion(), // Enable mouse support
 	)
 
 	finalModel, err := p.Run()
 	if err != nil {
+		if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+			return nil
+		}
 		return fmt.Errorf("error running TUI: %w", err)
 	}
 